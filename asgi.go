@@ -0,0 +1,109 @@
+package pythonstart
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// ASGIServer describes how an ASGI application is served by a server
+// package.
+type ASGIServer struct {
+	Name    string
+	command string
+}
+
+// Command returns the shell command that serves the given application on
+// $PORT, or on the given port when $PORT is not set.
+func (s ASGIServer) Command(app AppObject, defaultPort int) string {
+	return fmt.Sprintf(s.command, app, defaultPort)
+}
+
+var (
+	// asgiServers are listed in order of preference.
+	asgiServers = []ASGIServer{
+		{Name: "uvicorn", command: "uvicorn %s --host 0.0.0.0 --port ${PORT:-%d}"},
+		{Name: "hypercorn", command: "hypercorn %s --bind 0.0.0.0:${PORT:-%d}"},
+		{Name: "daphne", command: "daphne %s --bind 0.0.0.0 --port ${PORT:-%d}"},
+	}
+
+	asgiApplicationPattern = regexp.MustCompile(`(?m)^ASGI_APPLICATION\s*=\s*['"]([\w.]+)\.(\w+)['"]`)
+	asgiInstancePattern    = regexp.MustCompile(`(?m)^([A-Za-z_]\w*)\s*(?::[^=]*)?=\s*(?:[\w.]+\.)?(FastAPI|Starlette|Quart|Litestar|ProtocolTypeRouter)\(`)
+	asgiCallableNames      = []string{"application", "app"}
+	asgiModuleCandidates   = []string{"main.py", "app.py", "server.py", "api.py"}
+)
+
+// FindASGIApp locates the ASGI application object in the given directory.
+// The sources are checked in order:
+//
+//  1. a module:callable reference configured with BP_PYTHON_ASGI_APP
+//  2. the ASGI_APPLICATION setting of a Django Channels project
+//  3. an application or app callable in an asgi.py file at the top level or
+//     in a package directory
+//  4. a FastAPI, Starlette, Quart, Litestar or Channels ProtocolTypeRouter
+//     instance assigned in a main.py, app.py, server.py or api.py file at the
+//     top level or in a package directory
+//
+// The boolean result is false when no ASGI application can be found.
+func FindASGIApp(workingDir, reference string) (AppObject, bool, error) {
+	if reference != "" {
+		app, err := parseAppReference(reference)
+		if err != nil {
+			return AppObject{}, false, err
+		}
+		app.Source = ASGIAppEnv
+		return app, true, nil
+	}
+
+	app, found, err := findSettingReference(workingDir, asgiApplicationPattern, "ASGI_APPLICATION")
+	if err != nil || found {
+		return app, found, err
+	}
+
+	candidates, err := candidateFiles(workingDir, []string{"asgi.py"})
+	if err != nil {
+		return AppObject{}, false, err
+	}
+
+	for _, path := range candidates {
+		app, found, err := findCallable(workingDir, path, asgiCallableNames)
+		if err != nil || found {
+			return app, found, err
+		}
+	}
+
+	candidates, err = candidateFiles(workingDir, asgiModuleCandidates)
+	if err != nil {
+		return AppObject{}, false, err
+	}
+
+	for _, path := range candidates {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return AppObject{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		matches := asgiInstancePattern.FindSubmatch(content)
+		if matches != nil {
+			relPath := relativePath(workingDir, path)
+			return AppObject{
+				Module:   moduleName(relPath),
+				Callable: string(matches[1]),
+				Source:   fmt.Sprintf("%s instance in %s", matches[2], relPath),
+			}, true, nil
+		}
+	}
+
+	return AppObject{}, false, nil
+}
+
+// SelectASGIServer returns the preferred ASGI server among the declared
+// dependencies.
+func SelectASGIServer(dependencies Dependencies) (ASGIServer, bool) {
+	for _, server := range asgiServers {
+		if dependencies.Has(server.Name) {
+			return server, true
+		}
+	}
+	return ASGIServer{}, false
+}
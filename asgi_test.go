@@ -0,0 +1,158 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testASGI(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("FindASGIApp", func() {
+		context("when main.py creates a FastAPI app", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "main.py"), []byte("from fastapi import FastAPI\n\napi = FastAPI(title=\"service\")\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the app object", func() {
+				app, found, err := pythonstart.FindASGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "main",
+					Callable: "api",
+					Source:   "FastAPI instance in main.py",
+				}))
+			})
+		})
+
+		context("when a package creates a Starlette app", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "service"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "service", "app.py"), []byte("app: Starlette = starlette.applications.Starlette(routes=routes)\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the app object", func() {
+				app, found, err := pythonstart.FindASGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "service.app",
+					Callable: "app",
+					Source:   "Starlette instance in service/app.py",
+				}))
+			})
+		})
+
+		context("when a Django project has an asgi.py", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "mysite"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "mysite", "asgi.py"), []byte("application = get_asgi_application()\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the application", func() {
+				app, found, err := pythonstart.FindASGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "mysite.asgi",
+					Callable: "application",
+					Source:   "mysite/asgi.py",
+				}))
+			})
+		})
+
+		context("when a Django Channels project sets ASGI_APPLICATION", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "mysite"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "mysite", "settings.py"), []byte("ASGI_APPLICATION = \"mysite.routing.application\"\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "mysite", "asgi.py"), []byte("application = get_asgi_application()\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("uses the setting", func() {
+				app, found, err := pythonstart.FindASGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "mysite.routing",
+					Callable: "application",
+					Source:   "ASGI_APPLICATION in mysite/settings.py",
+				}))
+			})
+		})
+
+		context("when a reference is configured", func() {
+			it("uses the reference", func() {
+				app, found, err := pythonstart.FindASGIApp(workingDir, "service.main:create_app")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "service.main",
+					Callable: "create_app",
+					Source:   "BP_PYTHON_ASGI_APP",
+				}))
+			})
+		})
+
+		context("when there is no ASGI app", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "main.py"), []byte("app = Flask(__name__)\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not find an app", func() {
+				_, found, err := pythonstart.FindASGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the configured reference is malformed", func() {
+				it("returns an error", func() {
+					_, _, err := pythonstart.FindASGIApp(workingDir, "main:")
+					Expect(err).To(MatchError("expected <module>:<callable>"))
+				})
+			})
+		})
+	})
+
+	context("SelectASGIServer", func() {
+		it("prefers uvicorn over hypercorn and daphne", func() {
+			server, ok := pythonstart.SelectASGIServer(pythonstart.Dependencies{"daphne": true, "hypercorn": true, "uvicorn": true})
+			Expect(ok).To(BeTrue())
+			Expect(server.Name).To(Equal("uvicorn"))
+			Expect(server.Command(pythonstart.AppObject{Module: "main", Callable: "app"}, 8080)).To(Equal("uvicorn main:app --host 0.0.0.0 --port ${PORT:-8080}"))
+		})
+
+		it("selects hypercorn when it is the only server", func() {
+			server, ok := pythonstart.SelectASGIServer(pythonstart.Dependencies{"hypercorn": true})
+			Expect(ok).To(BeTrue())
+			Expect(server.Command(pythonstart.AppObject{Module: "main", Callable: "app"}, 8080)).To(Equal("hypercorn main:app --bind 0.0.0.0:${PORT:-8080}"))
+		})
+
+		it("returns false when no server is declared", func() {
+			_, ok := pythonstart.SelectASGIServer(pythonstart.Dependencies{"fastapi": true})
+			Expect(ok).To(BeFalse())
+		})
+	})
+}
@@ -1,33 +1,655 @@
 package pythonstart
 
 import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
 	"github.com/paketo-buildpacks/packit/v2/scribe"
 )
 
+// LaunchEnvLayer is the name of the layer holding the default launch
+// environment of the Python runtime.
+const LaunchEnvLayer = "launch-env"
+
+// launchEnvDefaults are the Python runtime settings every process starts
+// with. They are written as defaults, so the environment of the running
+// container takes precedence over them.
+var launchEnvDefaults = []struct {
+	name  string
+	value string
+}{
+	// Write output as it is produced rather than when a buffer fills, so logs
+	// reach the container runtime in time.
+	{"PYTHONUNBUFFERED", "1"},
+	// Do not write .pyc files into the read-only application directory.
+	{"PYTHONDONTWRITEBYTECODE", "1"},
+	// Dump the Python traceback when the interpreter crashes on a fatal signal.
+	{"PYTHONFAULTHANDLER", "1"},
+}
+
+// ProcessEnvLayer is the name of the layer holding the launch environment of
+// the process types configured with BP_PYTHON_PROCESS_ENV.
+const ProcessEnvLayer = "process-env"
+
 // Build will return a packit.BuildFunc that will be invoked during the build
 // phase of the buildpack lifecycle.
 //
-// Build assigns the image a launch process to run the Python REPL.
+// Build assigns the image the launch processes declared in a Procfile, if one
+// exists. The web process is set from BP_PYTHON_START_COMMAND when it is
+// present in the build environment. Otherwise, if the Procfile does not
+// declare one, the web process serves a discovered ASGI or WSGI application
+// or runs the entrypoint inferred from the application source,
+// falling back to the Python REPL when no entrypoint can be found. When
+// BP_PYTHON_APP_ROOT is set, the application is read from, and every process
+// runs in, that directory of the workspace. When the PDM or Hatch plan was
+// resolved during detection, every process runs inside the project
+// environment, and for Hatch the scripts of its default environment become
+// process types. A project of Jupyter notebooks alone is served by JupyterLab
+// or Voila, and a configured notebook is executed by a notebook process. When
+// enabled, a debug process runs the web process under
+// debugpy and a reload process restarts the default process when files
+// change. Finally, the working directories and launch environment
+// variables configured for individual process types are applied, and
+// defaults for the launch environment of the Python runtime are written to
+// a launch layer.
 func Build(logger scribe.Emitter) packit.BuildFunc {
 	return func(context packit.BuildContext) (packit.BuildResult, error) {
 		logger.Title("%s %s", context.BuildpackInfo.Name, context.BuildpackInfo.Version)
 
-		processes := []packit.Process{
-			{
-				Type:    "web",
-				Command: "python",
-				Default: true,
-				Direct:  true,
-			},
+		config, err := NewConfigurationLoader().Load(context.WorkingDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		config.Log(logger)
+
+		appDir, err := config.AppDir(context.WorkingDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		report, hasReport, err := ReadDetectionReport(context.Plan.Entries)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if hasReport {
+			report.Log(logger)
+
+			if config.DetectionReportEnabled {
+				err = report.WriteJSON(filepath.Join(context.Layers.Path, DetectionReportFile))
+				if err != nil {
+					return packit.BuildResult{}, fmt.Errorf("failed to write detection report: %w", err)
+				}
+			}
+		}
+
+		procfilePath := filepath.Join(appDir, "Procfile")
+		hasProcfile, err := fs.Exists(procfilePath)
+		if err != nil {
+			return packit.BuildResult{}, fmt.Errorf("failed to stat Procfile: %w", err)
+		}
+
+		dependencies, err := LoadDependencies(appDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		django, isDjango, err := FindDjangoProject(appDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		if isDjango {
+			logger.Process("Detected Django project")
+			logger.Subprocess("Settings module: %s", django.SettingsModule)
+			logger.Break()
 		}
 
-		logger.LaunchProcesses(processes)
+		var (
+			processes []packit.Process
+			webIsREPL bool
+		)
+		if hasProcfile {
+			logger.Process("Reading process types from Procfile")
+			processes, err = ParseProcfile(procfilePath)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+			logger.Subprocess("Found %d process type(s)", len(processes))
+			logger.Break()
+		}
+
+		if config.StartCommand != "" {
+			logger.Process("Using start command from %s", StartCommandEnv)
+			web, err := newProcess("web", config.StartCommand)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+			web.Default = true
+			logger.Subprocess(config.StartCommand)
+			logger.Break()
+
+			processes = setProcess(processes, web)
+		} else if !hasProcess(processes, "web") {
+			web, found, err := inferWebProcess(appDir, config, dependencies, isDjango, logger)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+			webIsREPL = !found
+
+			processes = setProcess(processes, web)
+		}
+
+		if isDjango {
+			for _, process := range django.ManagementProcesses(config.DefaultPort) {
+				if !hasProcess(processes, process.Type) {
+					processes = append(processes, process)
+				}
+			}
+		}
+
+		processes, err = addWorkerProcesses(processes, appDir, config, dependencies, logger)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		scripts, err := LoadConsoleScripts(appDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		processes = addConsoleScripts(processes, scripts, "pyproject.toml", logger)
+
+		setuptoolsScripts, err := LoadSetuptoolsScripts(appDir)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		processes = addConsoleScripts(processes, setuptoolsScripts, "setup.cfg and setup.py", logger)
+		scripts = append(scripts, setuptoolsScripts...)
+
+		if config.Notebook != "" {
+			processes, err = addNotebookProcess(processes, appDir, config, dependencies, logger)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		var debugAdded bool
+		if config.DebugEnabled {
+			processes, debugAdded = addDebugProcess(processes, config, logger)
+		}
+
+		if runner, ok := environmentRunners[report.Plan]; hasReport && ok {
+			logger.Process("Running process types in the project environment with %s run", runner)
+			for i := range processes {
+				processes[i] = runInEnvironment(processes[i], runner)
+			}
+			logger.Break()
+		}
+
+		if hasReport && report.Plan == HatchPlan {
+			hatchScripts, err := LoadHatchScripts(appDir)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			if len(hatchScripts) > 0 {
+				logger.Process("Adding Hatch scripts from pyproject.toml")
+				for _, script := range hatchScripts {
+					if hasProcess(processes, script.Name) {
+						logger.Subprocess("Skipping %s: process type already assigned", script.Name)
+						continue
+					}
+					logger.Subprocess(script.Name)
+					processes = append(processes, script.Process())
+				}
+				logger.Break()
+			}
+		}
+
+		defaultProcess := config.DefaultProcess
+		if defaultProcess == "" && webIsREPL && len(scripts) > 0 {
+			defaultProcess = scripts[0].Name
+		}
+
+		if defaultProcess != "" {
+			processes, err = setDefaultProcess(processes, defaultProcess)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		// Processes start in the workspace, so those of an application in a
+		// subdirectory are moved into it.
+		if config.AppRoot != "" {
+			for i := range processes {
+				processes[i].WorkingDirectory = appDir
+			}
+		}
+
+		// The reload process starts in the directory of the process it runs,
+		// so the working directories are applied before it is added.
+		for _, processType := range sortedKeys(config.ProcessWorkingDirectories) {
+			if i, ok := findProcess(processes, processType); ok {
+				processes[i].WorkingDirectory = filepath.Join(appDir, config.ProcessWorkingDirectories[processType])
+			}
+		}
+
+		// The debug process runs the web process, so it starts in the same
+		// directory unless it has one of its own.
+		if _, ok := config.ProcessWorkingDirectories["debug"]; debugAdded && !ok {
+			web, _ := findProcess(processes, "web")
+			debug, _ := findProcess(processes, "debug")
+			processes[debug].WorkingDirectory = processes[web].WorkingDirectory
+		}
+
+		if config.LiveReloadEnabled {
+			processes, err = addReloadProcess(processes, appDir, config, logger)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+		}
+
+		// Every process type is assigned now, so the working directories are
+		// checked against all of them, as the process environment is below.
+		for _, processType := range sortedKeys(config.ProcessWorkingDirectories) {
+			i, ok := findProcess(processes, processType)
+			if !ok {
+				return packit.BuildResult{}, fmt.Errorf("failed to apply %s: %q does not match any process type (%s)", ProcessDirEnv, processType, processTypes(processes))
+			}
+			processes[i].WorkingDirectory = filepath.Join(appDir, config.ProcessWorkingDirectories[processType])
+		}
+
+		launchEnvLayer, err := context.Layers.Get(LaunchEnvLayer)
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+
+		launchEnvLayer, err = launchEnvLayer.Reset()
+		if err != nil {
+			return packit.BuildResult{}, err
+		}
+		launchEnvLayer.Launch = true
+
+		for _, variable := range launchEnvDefaults {
+			launchEnvLayer.LaunchEnv.Default(variable.name, variable.value)
+		}
+		// Inferred servers fall back to the default port themselves, but
+		// applications that read $PORT need it set.
+		launchEnvLayer.LaunchEnv.Default("PORT", strconv.Itoa(config.DefaultPort))
+		if config.DebugEnabled {
+			launchEnvLayer.LaunchEnv.Default("DEBUG_PORT", strconv.Itoa(config.DebugPort))
+		}
+		logger.EnvironmentVariables(launchEnvLayer)
+
+		layers := []packit.Layer{launchEnvLayer}
+
+		// The debug process runs the web process, so it inherits its launch
+		// environment, with its own variables taking precedence.
+		processEnv := map[string]map[string]string{}
+		for processType, variables := range config.ProcessEnv {
+			processEnv[processType] = variables
+		}
+		if web, ok := config.ProcessEnv["web"]; debugAdded && ok {
+			debug := map[string]string{}
+			for name, value := range web {
+				debug[name] = value
+			}
+			for name, value := range config.ProcessEnv["debug"] {
+				debug[name] = value
+			}
+			processEnv["debug"] = debug
+		}
+
+		var processEnvs []map[string]packit.Environment
+		if len(processEnv) > 0 {
+			processEnvLayer, err := context.Layers.Get(ProcessEnvLayer)
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+
+			processEnvLayer, err = processEnvLayer.Reset()
+			if err != nil {
+				return packit.BuildResult{}, err
+			}
+			processEnvLayer.Launch = true
+
+			for _, processType := range sortedKeys(processEnv) {
+				if !hasProcess(processes, processType) {
+					return packit.BuildResult{}, fmt.Errorf("failed to apply %s: %q does not match any process type (%s)", ProcessEnvEnv, processType, processTypes(processes))
+				}
+
+				env := packit.Environment{}
+				for name, value := range processEnv[processType] {
+					env.Override(name, value)
+				}
+				processEnvLayer.ProcessLaunchEnv[processType] = env
+			}
+
+			layers = append(layers, processEnvLayer)
+			processEnvs = append(processEnvs, processEnvLayer.ProcessLaunchEnv)
+		}
+
+		logger.LaunchProcesses(processes, processEnvs...)
 
 		return packit.BuildResult{
+			Layers: layers,
 			Launch: packit.LaunchMetadata{
 				Processes: processes,
 			},
 		}, nil
 	}
 }
+
+// inferWebProcess selects the web process for an application that does not
+// declare one. An ASGI application is served with the first declared ASGI
+// server, a WSGI application is served with gunicorn when gunicorn is
+// declared as a dependency, and a Streamlit, Gradio, Panel or Dash dashboard
+// is served by its framework when the framework is declared as a dependency.
+// Otherwise the application runs the inferred entrypoint; a Django project
+// is not served with its development server unless that is chosen
+// explicitly.
+func inferWebProcess(workingDir string, config Configuration, dependencies Dependencies, isDjango bool, logger scribe.Emitter) (packit.Process, bool, error) {
+	asgiApp, found, err := FindASGIApp(workingDir, config.ASGIApp)
+	if err != nil {
+		return packit.Process{}, false, err
+	}
+
+	if found {
+		logger.Process("Discovering ASGI application")
+		logger.Subprocess("Found %s (%s)", asgiApp, asgiApp.Source)
+
+		server, ok := SelectASGIServer(dependencies)
+		if ok {
+			logger.Subprocess("Server:   %s", server.Name)
+			logger.Subprocess("Module:   %s", asgiApp.Module)
+			logger.Subprocess("Callable: %s", asgiApp.Callable)
+			logger.Break()
+
+			return packit.Process{
+				Type:    "web",
+				Command: server.Command(asgiApp, config.DefaultPort),
+				Default: true,
+			}, true, nil
+		}
+
+		logger.Subprocess("Skipping: no ASGI server (uvicorn, hypercorn or daphne) is declared as a dependency")
+		logger.Break()
+	}
+
+	wsgiApp, found, err := FindWSGIApp(workingDir, config.WSGIApp)
+	if err != nil {
+		return packit.Process{}, false, err
+	}
+
+	if found {
+		logger.Process("Discovering WSGI application")
+		logger.Subprocess("Found %s (%s)", wsgiApp, wsgiApp.Source)
+
+		if dependencies.Has("gunicorn") {
+			logger.Break()
+			return packit.Process{
+				Type:    "web",
+				Command: fmt.Sprintf("gunicorn %s --bind 0.0.0.0:${PORT:-%d}", wsgiApp, config.DefaultPort),
+				Default: true,
+			}, true, nil
+		}
+
+		logger.Subprocess("Skipping: gunicorn is not declared as a dependency")
+		logger.Break()
+	}
+
+	dashboard, found, err := FindDashboard(workingDir)
+	if err != nil {
+		return packit.Process{}, false, err
+	}
+
+	if found {
+		logger.Process("Discovering dashboard application")
+		logger.Subprocess("Found %s application %s", dashboard.Framework.Name, dashboard.Script)
+
+		if dependencies.Has(dashboard.Framework.Package) {
+			logger.Break()
+			return dashboard.Process(config.DefaultPort), true, nil
+		}
+
+		logger.Subprocess("Skipping: %s is not declared as a dependency", dashboard.Framework.Package)
+		logger.Break()
+	}
+
+	if isDjango {
+		logger.Process("Skipping Django development server for the web process")
+		logger.Subprocess("Declare gunicorn or an ASGI server as a dependency to serve the project")
+		logger.Subprocess("Set %s=runserver to use the development server instead", DefaultProcessEnv)
+		logger.Break()
+	}
+
+	entrypoint, err := InferEntrypoint(workingDir)
+	if err != nil {
+		return packit.Process{}, false, err
+	}
+
+	// A project of notebooks alone is served by a notebook server rather
+	// than the Python REPL.
+	if len(entrypoint.Args) == 0 {
+		notebooks, err := FindNotebooks(workingDir, config.SourceDepth)
+		if err != nil {
+			return packit.Process{}, false, err
+		}
+
+		if len(notebooks) > 0 {
+			server := SelectNotebookServer(config, dependencies)
+			logger.Process("Serving Jupyter notebooks")
+			logger.Subprocess("Found %d notebook(s)", len(notebooks))
+			logger.Subprocess("Server: %s", server)
+			logger.Subprocess("Token:  %s", config.NotebookToken)
+			logger.Break()
+
+			return NotebookServerProcess(server, config), true, nil
+		}
+	}
+
+	logger.Process("Inferring start command")
+	logger.Subprocess(entrypoint.Rule)
+	logger.Break()
+
+	return packit.Process{
+		Type:    "web",
+		Command: entrypoint.Command,
+		Args:    entrypoint.Args,
+		Default: true,
+		Direct:  true,
+	}, len(entrypoint.Args) > 0, nil
+}
+
+// addNotebookProcess appends a notebook process that executes the configured
+// notebook, unless a notebook process is already assigned.
+func addNotebookProcess(processes []packit.Process, appDir string, config Configuration, dependencies Dependencies, logger scribe.Emitter) ([]packit.Process, error) {
+	exists, err := fs.Exists(filepath.Join(appDir, config.Notebook))
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s notebook: %w", NotebookEnv, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("failed to find %s notebook: %s does not exist", NotebookEnv, config.Notebook)
+	}
+
+	if hasProcess(processes, "notebook") {
+		logger.Process("Skipping notebook process: process type already assigned")
+		logger.Break()
+		return processes, nil
+	}
+
+	executor := SelectNotebookExecutor(config, dependencies)
+	logger.Process("Adding notebook process")
+	logger.Subprocess("Executing %s with %s", config.Notebook, executor)
+	logger.Break()
+
+	return append(processes, NotebookProcess(executor, config)), nil
+}
+
+// addWorkerProcesses appends the worker process types of the task queue
+// application, skipping those whose process type is already assigned.
+func addWorkerProcesses(processes []packit.Process, appDir string, config Configuration, dependencies Dependencies, logger scribe.Emitter) ([]packit.Process, error) {
+	worker, found, err := FindWorkerApp(appDir, dependencies)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return processes, nil
+	}
+
+	logger.Process("Adding %s worker process types for %s (%s)", worker.Queue.Name, worker.Reference(), worker.App.Source)
+	for _, process := range worker.Processes(config.WorkerConcurrency) {
+		if hasProcess(processes, process.Type) {
+			logger.Subprocess("Skipping %s: process type already assigned", process.Type)
+			continue
+		}
+		logger.Subprocess(process.Type)
+		processes = append(processes, process)
+	}
+	if config.WorkerConcurrency > 0 {
+		logger.Subprocess("Concurrency: %d", config.WorkerConcurrency)
+	}
+	logger.Break()
+
+	return processes, nil
+}
+
+// addDebugProcess appends a debug process that runs the web process under
+// debugpy, unless a debug process is already assigned or the web process does
+// not run Python. The boolean result reports whether the process was added.
+func addDebugProcess(processes []packit.Process, config Configuration, logger scribe.Emitter) ([]packit.Process, bool) {
+	if hasProcess(processes, "debug") {
+		logger.Process("Skipping debug process: process type already assigned")
+		logger.Break()
+		return processes, false
+	}
+
+	i, _ := findProcess(processes, "web")
+	debug, ok := DebugProcess(processes[i], config)
+	if !ok {
+		logger.Process("Skipping debug process: the web process does not run a Python script, module or known server")
+		logger.Break()
+		return processes, false
+	}
+
+	logger.Process("Adding debug process for web")
+	logger.Subprocess("Listening on port %d unless DEBUG_PORT is set", config.DebugPort)
+	if config.DebugWaitForClient {
+		logger.Subprocess("Waiting for a debugger to attach before starting")
+	}
+	logger.Break()
+
+	return append(processes, debug), true
+}
+
+// addReloadProcess appends a reload process that runs the default process
+// under watchexec and makes it the default, so that the application restarts
+// when its files change while the wrapped process remains available.
+func addReloadProcess(processes []packit.Process, appDir string, config Configuration, logger scribe.Emitter) ([]packit.Process, error) {
+	if hasProcess(processes, "reload") {
+		logger.Process("Skipping reload process: process type already assigned")
+		logger.Break()
+		return processes, nil
+	}
+
+	for _, process := range processes {
+		if !process.Default {
+			continue
+		}
+
+		reload := ReloadProcess(process, appDir, config)
+		logger.Process("Adding reload process for %s", process.Type)
+		logger.Subprocess("Watching: %s", strings.Join(config.ReloadWatchPaths, ", "))
+		logger.Subprocess("Ignoring: %s", strings.Join(config.ReloadIgnore, ", "))
+		logger.Subprocess("Debounce: %s", config.ReloadDebounce)
+		logger.Subprocess("Signal:   %s", config.ReloadSignal)
+		logger.Break()
+
+		return setDefaultProcess(append(processes, reload), reload.Type)
+	}
+
+	return processes, nil
+}
+
+// addConsoleScripts appends the processes of the given scripts, skipping
+// those whose process type is already assigned.
+func addConsoleScripts(processes []packit.Process, scripts []ConsoleScript, source string, logger scribe.Emitter) []packit.Process {
+	if len(scripts) == 0 {
+		return processes
+	}
+
+	logger.Process("Adding console scripts from %s", source)
+	for _, script := range scripts {
+		if hasProcess(processes, script.Name) {
+			logger.Subprocess("Skipping %s: process type already assigned", script.Name)
+			continue
+		}
+		logger.Subprocess(script.Name)
+		processes = append(processes, script.Process())
+	}
+	logger.Break()
+
+	return processes
+}
+
+func hasProcess(processes []packit.Process, processType string) bool {
+	_, ok := findProcess(processes, processType)
+	return ok
+}
+
+func findProcess(processes []packit.Process, processType string) (int, bool) {
+	for i, process := range processes {
+		if process.Type == processType {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// processTypes lists the types of the given processes for error messages.
+func processTypes(processes []packit.Process) string {
+	var types []string
+	for _, process := range processes {
+		types = append(types, process.Type)
+	}
+	return strings.Join(types, ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setDefaultProcess marks the process of the given type as the only default
+// process.
+func setDefaultProcess(processes []packit.Process, processType string) ([]packit.Process, error) {
+	if !hasProcess(processes, processType) {
+		return nil, fmt.Errorf("failed to set default process: %q does not match any process type (%s)", processType, processTypes(processes))
+	}
+
+	for i := range processes {
+		processes[i].Default = processes[i].Type == processType
+	}
+	return processes, nil
+}
+
+// setProcess replaces the process of the same type in the given list, or
+// prepends the process when there is none.
+func setProcess(processes []packit.Process, process packit.Process) []packit.Process {
+	for i := range processes {
+		if processes[i].Type == process.Type {
+			processes[i] = process
+			return processes
+		}
+	}
+	return append([]packit.Process{process}, processes...)
+}
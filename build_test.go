@@ -2,7 +2,9 @@ package pythonstart_test
 
 import (
 	"bytes"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/paketo-buildpacks/packit/v2"
@@ -68,7 +70,22 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 			Plan: packit.BuildpackPlan{
 				Entries: nil,
 			},
-			Layers: nil,
+			Layers: []packit.Layer{
+				{
+					Path:      filepath.Join(layersDir, pythonstart.LaunchEnvLayer),
+					Name:      pythonstart.LaunchEnvLayer,
+					Launch:    true,
+					SharedEnv: packit.Environment{},
+					BuildEnv:  packit.Environment{},
+					LaunchEnv: packit.Environment{
+						"PYTHONUNBUFFERED.default":        "1",
+						"PYTHONDONTWRITEBYTECODE.default": "1",
+						"PYTHONFAULTHANDLER.default":      "1",
+						"PORT.default":                    "8080",
+					},
+					ProcessLaunchEnv: map[string]packit.Environment{},
+				},
+			},
 			Launch: packit.LaunchMetadata{
 				Processes: []packit.Process{
 					{
@@ -82,8 +99,1332 @@ func testBuild(t *testing.T, context spec.G, it spec.S) {
 		}))
 
 		Expect(buffer.String()).To(ContainSubstring("Some Buildpack some-version"))
+		Expect(buffer.String()).To(ContainSubstring("Build configuration:"))
+		Expect(buffer.String()).To(ContainSubstring("Configuring launch environment"))
+		Expect(buffer.String()).To(ContainSubstring(`PYTHONUNBUFFERED        -> "1"`))
+		Expect(buffer.String()).To(ContainSubstring(`PYTHONDONTWRITEBYTECODE -> "1"`))
+		Expect(buffer.String()).To(ContainSubstring(`PYTHONFAULTHANDLER      -> "1"`))
+		Expect(buffer.String()).To(ContainSubstring(`PORT                    -> "8080"`))
 		Expect(buffer.String()).To(ContainSubstring("Assigning launch processes:"))
+		Expect(buffer.String()).To(ContainSubstring("No entrypoint found, falling back to the Python REPL"))
 		Expect(buffer.String()).To(ContainSubstring("web (default): python"))
 	})
 
+	context("when the app has an inferable entrypoint", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "server.py"), []byte{}, os.ModePerm)).To(Succeed())
+		})
+
+		it("returns a result that runs the entrypoint", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				CNBPath:    cnbDir,
+				Stack:      "some-stack",
+				BuildpackInfo: packit.BuildpackInfo{
+					Name:    "Some Buildpack",
+					Version: "some-version",
+				},
+				Plan: packit.BuildpackPlan{
+					Entries: []packit.BuildpackPlanEntry{},
+				},
+				Layers: packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Args:    []string{"server.py"},
+					Default: true,
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Found conventional entrypoint server.py"))
+			Expect(buffer.String()).To(ContainSubstring("web (default): python server.py"))
+		})
+	})
+
+	context("when the app has a Procfile", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("web: gunicorn app:app\nworker: python worker.py\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("returns a result with the Procfile processes", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "gunicorn",
+					Args:    []string{"app:app"},
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "worker",
+					Command: "python",
+					Args:    []string{"worker.py"},
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Reading process types from Procfile"))
+			Expect(buffer.String()).NotTo(ContainSubstring("Inferring start command"))
+		})
+
+		context("when the Procfile has no web entry", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("worker: python worker.py\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("adds the inferred web process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(Equal([]packit.Process{
+					{
+						Type:    "web",
+						Command: "python",
+						Args:    []string{"app.py"},
+						Default: true,
+						Direct:  true,
+					},
+					{
+						Type:    "worker",
+						Command: "python",
+						Args:    []string{"worker.py"},
+						Direct:  true,
+					},
+				}))
+			})
+		})
+	})
+
+	context("when the app has an ASGI application", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "main.py"), []byte("app = FastAPI()\n"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("fastapi\nhypercorn\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("serves the application with the declared ASGI server", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "hypercorn main:app --bind 0.0.0.0:${PORT:-8080}",
+					Default: true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Discovering ASGI application"))
+			Expect(buffer.String()).To(ContainSubstring("Server:   hypercorn"))
+			Expect(buffer.String()).To(ContainSubstring("Module:   main"))
+			Expect(buffer.String()).To(ContainSubstring("Callable: app"))
+		})
+
+		context("when no ASGI server is declared", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("fastapi\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("falls back to entrypoint inference", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(Equal([]packit.Process{
+					{
+						Type:    "web",
+						Command: "python",
+						Args:    []string{"main.py"},
+						Default: true,
+						Direct:  true,
+					},
+				}))
+
+				Expect(buffer.String()).To(ContainSubstring("Skipping: no ASGI server"))
+			})
+		})
+	})
+
+	context("when the app has a WSGI application", func() {
+		it.Before(func() {
+			Expect(os.Mkdir(filepath.Join(workingDir, "module"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "module", "wsgi.py"), []byte("from .server import app\n"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("Flask==3.0.0\ngunicorn==23.0.0\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("serves the application with gunicorn", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "gunicorn module.wsgi:app --bind 0.0.0.0:${PORT:-8080}",
+					Default: true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Found module.wsgi:app (module/wsgi.py)"))
+			Expect(buffer.String()).NotTo(ContainSubstring("Inferring start command"))
+		})
+
+		context("when BP_PYTHON_DEFAULT_PORT is set", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.DefaultPortEnv, "5000")
+			})
+
+			it("binds to $PORT with the configured default and exports it", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(Equal([]packit.Process{
+					{
+						Type:    "web",
+						Command: "gunicorn module.wsgi:app --bind 0.0.0.0:${PORT:-5000}",
+						Default: true,
+					},
+				}))
+
+				Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("PORT.default", "5000"))
+			})
+		})
+
+		context("when gunicorn is not declared", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("Flask==3.0.0\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("falls back to entrypoint inference", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(Equal([]packit.Process{
+					{
+						Type:    "web",
+						Command: "python",
+						Default: true,
+						Direct:  true,
+					},
+				}))
+
+				Expect(buffer.String()).To(ContainSubstring("Skipping: gunicorn is not declared as a dependency"))
+			})
+		})
+	})
+
+	context("when the app is a Django project", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "manage.py"), []byte(`os.environ.setdefault("DJANGO_SETTINGS_MODULE", "mysite.settings")`), os.ModePerm)).To(Succeed())
+			Expect(os.Mkdir(filepath.Join(workingDir, "mysite"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "mysite", "settings.py"), []byte(`WSGI_APPLICATION = "mysite.wsgi.application"`), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("Django\ngunicorn\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("serves the project and adds management processes", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "gunicorn mysite.wsgi:application --bind 0.0.0.0:${PORT:-8080}",
+					Default: true,
+				},
+				{
+					Type:    "migrate",
+					Command: "python",
+					Args:    []string{"manage.py", "migrate"},
+					Direct:  true,
+				},
+				{
+					Type:    "shell",
+					Command: "python",
+					Args:    []string{"manage.py", "shell"},
+					Direct:  true,
+				},
+				{
+					Type:    "runserver",
+					Command: "python manage.py runserver 0.0.0.0:${PORT:-8080}",
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Detected Django project"))
+			Expect(buffer.String()).To(ContainSubstring("Settings module: mysite.settings"))
+		})
+
+		context("when no application server is declared", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("Django\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not serve the project with the development server", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes[0]).To(Equal(packit.Process{
+					Type:    "web",
+					Command: "python",
+					Default: true,
+					Direct:  true,
+				}))
+				Expect(result.Launch.Processes).To(ContainElement(packit.Process{
+					Type:    "runserver",
+					Command: "python manage.py runserver 0.0.0.0:${PORT:-8080}",
+				}))
+
+				Expect(buffer.String()).To(ContainSubstring("Skipping Django development server for the web process"))
+				Expect(buffer.String()).To(ContainSubstring("Inferring start command"))
+			})
+
+			context("when BP_PYTHON_DEFAULT_PROCESS=runserver", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.DefaultProcessEnv, "runserver")
+				})
+
+				it("launches the development server by default", func() {
+					result, err := build(packit.BuildContext{
+						WorkingDir: workingDir,
+						Layers:     packit.Layers{Path: layersDir},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result.Launch.Processes).To(ContainElement(packit.Process{
+						Type:    "runserver",
+						Command: "python manage.py runserver 0.0.0.0:${PORT:-8080}",
+						Default: true,
+					}))
+				})
+			})
+		})
+
+		context("when the Procfile declares a migrate process", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("migrate: python manage.py migrate --noinput\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("keeps the Procfile process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(ContainElement(packit.Process{
+					Type:    "migrate",
+					Command: "python",
+					Args:    []string{"manage.py", "migrate", "--noinput"},
+					Direct:  true,
+				}))
+				Expect(result.Launch.Processes).To(HaveLen(4))
+			})
+		})
+	})
+
+	context("when the app is a dashboard", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "streamlit_app.py"), []byte("import streamlit as st\n"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("streamlit==1.39.0\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("serves the dashboard headlessly with its framework", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "streamlit run streamlit_app.py --server.address=0.0.0.0 --server.port=${PORT:-8080} --server.headless=true",
+					Default: true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Found Streamlit application streamlit_app.py"))
+			Expect(buffer.String()).NotTo(ContainSubstring("Inferring start command"))
+		})
+
+		context("when BP_PYTHON_START_COMMAND is set", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.StartCommandEnv, "streamlit run streamlit_app.py --server.port=9000")
+			})
+
+			it("uses the start command for the web process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(Equal([]packit.Process{
+					{
+						Type:    "web",
+						Command: "streamlit",
+						Args:    []string{"run", "streamlit_app.py", "--server.port=9000"},
+						Default: true,
+						Direct:  true,
+					},
+				}))
+
+				Expect(buffer.String()).NotTo(ContainSubstring("Discovering dashboard application"))
+			})
+		})
+
+		context("when the framework is not declared", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("pandas\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("falls back to entrypoint inference", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(HaveLen(1))
+				Expect(result.Launch.Processes[0].Command).NotTo(ContainSubstring("streamlit"))
+
+				Expect(buffer.String()).To(ContainSubstring("Skipping: streamlit is not declared as a dependency"))
+				Expect(buffer.String()).To(ContainSubstring("Inferring start command"))
+			})
+		})
+	})
+
+	context("when the app has a task queue application", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("from celery import Celery\n\napp = Celery('tasks')\n"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("celery\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("adds worker process types", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "worker",
+					Command: "celery",
+					Args:    []string{"--app", "tasks:app", "worker", "--loglevel=INFO"},
+					Direct:  true,
+				},
+				{
+					Type:    "beat",
+					Command: "celery",
+					Args:    []string{"--app", "tasks:app", "beat", "--loglevel=INFO"},
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Adding Celery worker process types for tasks:app (tasks.py)"))
+		})
+
+		context("when BP_PYTHON_WORKER_CONCURRENCY is set", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.WorkerConcurrencyEnv, "8")
+			})
+
+			it("passes the concurrency to the worker", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes[1].Args).To(Equal([]string{"--app", "tasks:app", "worker", "--loglevel=INFO", "--concurrency", "8"}))
+				Expect(buffer.String()).To(ContainSubstring("Concurrency: 8"))
+			})
+		})
+
+		context("when the Procfile declares a worker process", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("worker: celery -A tasks worker --pool=solo\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("keeps the Procfile process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(ContainElement(packit.Process{
+					Type:    "worker",
+					Command: "celery",
+					Args:    []string{"-A", "tasks", "worker", "--pool=solo"},
+					Direct:  true,
+				}))
+				Expect(result.Launch.Processes).To(HaveLen(3))
+
+				Expect(buffer.String()).To(ContainSubstring("Skipping worker: process type already assigned"))
+			})
+		})
+	})
+
+	context("when pyproject.toml declares console scripts", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[project.scripts]\nserve = \"service.cli:serve\"\nimport-data = \"service.cli:import_data\"\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("adds a process per script and makes the first one the default", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Direct:  true,
+				},
+				{
+					Type:    "serve",
+					Command: "serve",
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "import-data",
+					Command: "import-data",
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Adding console scripts from pyproject.toml"))
+		})
+
+		context("when the web process runs an inferred entrypoint", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("keeps the web process as the default", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes[0].Default).To(BeTrue())
+				Expect(result.Launch.Processes[1].Default).To(BeFalse())
+			})
+		})
+
+		context("when BP_PYTHON_DEFAULT_PROCESS is set", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.DefaultProcessEnv, "import-data")
+			})
+
+			it("makes that process the default", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes[0].Default).To(BeFalse())
+				Expect(result.Launch.Processes[1].Default).To(BeFalse())
+				Expect(result.Launch.Processes[2].Default).To(BeTrue())
+				Expect(buffer.String()).To(ContainSubstring("import-data (default): import-data"))
+			})
+		})
+	})
+
+	context("when setup.cfg declares console scripts", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "setup.cfg"), []byte("[options.entry_points]\nconsole_scripts =\n    serve = service.cli:serve\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("adds a process type for each script", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Direct:  true,
+				},
+				{
+					Type:    "serve",
+					Command: "serve",
+					Default: true,
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Adding console scripts from setup.cfg and setup.py"))
+		})
+	})
+
+	context("when BP_PYTHON_START_COMMAND is set", func() {
+		it.Before(func() {
+			t.Setenv(pythonstart.StartCommandEnv, `python -m http.server "8080"`)
+			Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("web: gunicorn app:app\nworker: python worker.py\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("uses the start command for the web process", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Args:    []string{"-m", "http.server", "8080"},
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "worker",
+					Command: "python",
+					Args:    []string{"worker.py"},
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Using start command from BP_PYTHON_START_COMMAND"))
+			Expect(buffer.String()).NotTo(ContainSubstring("Inferring start command"))
+		})
+
+		context("when the start command needs a shell", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.StartCommandEnv, "gunicorn app:app --bind 0.0.0.0:$PORT")
+			})
+
+			it("runs the start command through a shell", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes[0]).To(Equal(packit.Process{
+					Type:    "web",
+					Command: "gunicorn app:app --bind 0.0.0.0:$PORT",
+					Default: true,
+				}))
+			})
+		})
+	})
+
+	context("when the start command is set in pyproject.toml", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[tool.paketo.python-start]\nstart-command = \"python app.py --verbose\"\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("uses the start command and logs where it came from", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Args:    []string{"app.py", "--verbose"},
+					Default: true,
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring(`BP_PYTHON_START_COMMAND             -> "python app.py --verbose" (pyproject.toml)`))
+		})
+	})
+
+	context("when BP_PYTHON_APP_ROOT is set", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(workingDir, "services", "api"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "services", "api", "server.py"), []byte{}, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "main.py"), []byte{}, os.ModePerm)).To(Succeed())
+			t.Setenv(pythonstart.AppRootEnv, "services/api")
+		})
+
+		it("reads the app root and runs the processes in it", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:             "web",
+					Command:          "python",
+					Args:             []string{"server.py"},
+					Default:          true,
+					Direct:           true,
+					WorkingDirectory: filepath.Join(workingDir, "services", "api"),
+				},
+			}))
+		})
+
+		context("when the pyproject.toml of the app root sets the start command", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "services", "api", "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+start-command = "python server.py --verbose"
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("uses it for the web process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(Equal([]packit.Process{
+					{
+						Type:             "web",
+						Command:          "python",
+						Args:             []string{"server.py", "--verbose"},
+						Default:          true,
+						Direct:           true,
+						WorkingDirectory: filepath.Join(workingDir, "services", "api"),
+					},
+				}))
+			})
+		})
+	})
+
+	context("when the plan carries a detection report", func() {
+		var entries []packit.BuildpackPlanEntry
+
+		it.Before(func() {
+			entries = []packit.BuildpackPlanEntry{
+				{
+					Name: pythonstart.DetectionReportPlanEntry,
+					Metadata: map[string]interface{}{
+						"plan":           "pip",
+						"files":          []interface{}{"requirements.txt", "pyproject.toml"},
+						"python-sources": int64(3),
+						"offered":        []interface{}{"pip", "pipenv", "conda", "pixi", "poetry"},
+						"suppressed": []map[string]interface{}{
+							{"plan": "simple", "reason": "Django project found"},
+						},
+					},
+				},
+			}
+		})
+
+		it("prints the report", func() {
+			_, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+				Plan:       packit.BuildpackPlan{Entries: entries},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(buffer.String()).To(ContainSubstring("Detection report:"))
+			Expect(buffer.String()).To(ContainSubstring("Resolved plan:  pip"))
+			Expect(buffer.String()).To(ContainSubstring("Files found:    requirements.txt, pyproject.toml"))
+			Expect(buffer.String()).To(ContainSubstring("Python sources: 3"))
+			Expect(buffer.String()).To(ContainSubstring("Plans offered:  pip, pipenv, conda, pixi, poetry"))
+			Expect(buffer.String()).To(ContainSubstring("Suppressed simple: Django project found"))
+			Expect(filepath.Join(layersDir, pythonstart.DetectionReportFile)).NotTo(BeAnExistingFile())
+		})
+
+		context("when BP_PYTHON_DETECTION_REPORT=true", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.DetectionReportEnv, "true")
+			})
+
+			it("writes the report to the layers directory", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+					Plan:       packit.BuildpackPlan{Entries: entries},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(layersDir, pythonstart.DetectionReportFile))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(MatchJSON(`{
+					"plan": "pip",
+					"files": ["requirements.txt", "pyproject.toml"],
+					"python_sources": 3,
+					"settings": null,
+					"offered": ["pip", "pipenv", "conda", "pixi", "poetry"],
+					"suppressed": [{"plan": "simple", "reason": "Django project found"}]
+				}`))
+			})
+		})
+	})
+
+	context("when the PDM plan was resolved", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("web: python server.py\nworker: celery -A tasks worker | tee worker.log\n"), os.ModePerm)).To(Succeed())
+		})
+
+		it("runs every process in the PDM environment", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+				Plan: packit.BuildpackPlan{
+					Entries: []packit.BuildpackPlanEntry{
+						{
+							Name:     pythonstart.DetectionReportPlanEntry,
+							Metadata: map[string]interface{}{"plan": "pdm"},
+						},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "pdm",
+					Args:    []string{"run", "python", "server.py"},
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "worker",
+					Command: "pdm",
+					Args:    []string{"run", "bash", "-c", "celery -A tasks worker | tee worker.log"},
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Running process types in the project environment with pdm run"))
+		})
+	})
+
+	context("when the Hatch plan was resolved", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "server.py"), []byte{}, os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.hatch.envs.default.scripts]
+web = "uvicorn app:app"
+seed = "python seed.py"
+`), os.ModePerm)).To(Succeed())
+		})
+
+		it("runs every process in the Hatch environment and adds the Hatch scripts", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+				Plan: packit.BuildpackPlan{
+					Entries: []packit.BuildpackPlanEntry{
+						{
+							Name:     pythonstart.DetectionReportPlanEntry,
+							Metadata: map[string]interface{}{"plan": "hatch"},
+						},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "hatch",
+					Args:    []string{"run", "python", "server.py"},
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "seed",
+					Command: "hatch",
+					Args:    []string{"run", "seed"},
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Adding Hatch scripts from pyproject.toml"))
+			Expect(buffer.String()).To(ContainSubstring("Skipping web: process type already assigned"))
+		})
+	})
+
+	context("when the app is a project of notebooks", func() {
+		it.Before(func() {
+			Expect(os.Mkdir(filepath.Join(workingDir, "notebooks"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "notebooks", "report.ipynb"), []byte("{}"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "environment.yml"), []byte("dependencies:\n  - voila\n  - papermill\n"), os.ModePerm)).To(Succeed())
+			t.Setenv(pythonstart.NotebookEnv, "notebooks/report.ipynb")
+		})
+
+		it("serves the notebooks and adds a notebook process", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "voila --no-browser --Voila.ip=0.0.0.0 --port=${PORT:-8080} --token",
+					Default: true,
+				},
+				{
+					Type:    "notebook",
+					Command: "papermill --log-output notebooks/report.ipynb ${TMPDIR:-/tmp}/report.ipynb",
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Serving Jupyter notebooks"))
+			Expect(buffer.String()).To(ContainSubstring("Server: voila"))
+			Expect(buffer.String()).To(ContainSubstring("Executing notebooks/report.ipynb with papermill"))
+			Expect(buffer.String()).NotTo(ContainSubstring("Inferring start command"))
+		})
+
+		context("when the app has an inferable entrypoint", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("runs the entrypoint", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes[0].Args).To(Equal([]string{"app.py"}))
+			})
+		})
+
+		context("when BP_PYTHON_NOTEBOOK does not exist", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.NotebookEnv, "missing.ipynb")
+			})
+
+			it("returns an error", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError("failed to find BP_PYTHON_NOTEBOOK notebook: missing.ipynb does not exist"))
+			})
+		})
+	})
+
+	context("when BP_LIVE_RELOAD_ENABLED=true", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "server.py"), []byte{}, os.ModePerm)).To(Succeed())
+			t.Setenv(pythonstart.LiveReloadEnv, "true")
+			t.Setenv(pythonstart.ReloadPathsEnv, "src templates")
+			t.Setenv(pythonstart.ReloadSignalEnv, "int")
+		})
+
+		it("adds a default reload process and keeps the web process", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Args:    []string{"server.py"},
+					Direct:  true,
+				},
+				{
+					Type:    "reload",
+					Command: "watchexec",
+					Args: []string{
+						"--restart",
+						"--debounce", "500ms",
+						"--stop-signal", "SIGINT",
+						"--shell", "none",
+						"--watch", filepath.Join(workingDir, "src"),
+						"--watch", filepath.Join(workingDir, "templates"),
+						"--ignore", "__pycache__",
+						"--ignore", "*.pyc",
+						"--ignore", ".venv",
+						"--",
+						"python", "server.py",
+					},
+					Default: true,
+					Direct:  true,
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("Adding reload process for web"))
+			Expect(buffer.String()).To(ContainSubstring("Watching: src, templates"))
+			Expect(buffer.String()).To(ContainSubstring("Ignoring: __pycache__, *.pyc, .venv"))
+		})
+
+		context("when process settings are configured for the reload process", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "src"), os.ModePerm)).To(Succeed())
+				t.Setenv(pythonstart.ProcessDirEnv, "web:src reload:.")
+				t.Setenv(pythonstart.ProcessEnvEnv, "reload:RELOADING=1")
+			})
+
+			it("applies them to the reload process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(HaveLen(2))
+				Expect(result.Launch.Processes[0].WorkingDirectory).To(Equal(filepath.Join(workingDir, "src")))
+				Expect(result.Launch.Processes[1].Type).To(Equal("reload"))
+				Expect(result.Launch.Processes[1].WorkingDirectory).To(Equal(workingDir))
+				Expect(result.Layers[1].ProcessLaunchEnv).To(HaveKeyWithValue("reload", packit.Environment{"RELOADING.override": "1"}))
+			})
+
+			context("when only the wrapped process has a working directory", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.ProcessDirEnv, "web:src")
+				})
+
+				it("runs the reload process in the same directory", func() {
+					result, err := build(packit.BuildContext{
+						WorkingDir: workingDir,
+						Layers:     packit.Layers{Path: layersDir},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(result.Launch.Processes[1].WorkingDirectory).To(Equal(filepath.Join(workingDir, "src")))
+				})
+			})
+		})
+
+		context("when the Procfile declares a reload process", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("reload: watchexec -r python server.py\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("keeps the declared process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(HaveLen(2))
+				Expect(result.Launch.Processes[1].Args).To(Equal([]string{"-r", "python", "server.py"}))
+				Expect(buffer.String()).To(ContainSubstring("Skipping reload process: process type already assigned"))
+			})
+		})
+	})
+
+	context("when BP_PYTHON_DEBUG_ENABLED=true", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "server.py"), []byte{}, os.ModePerm)).To(Succeed())
+			t.Setenv(pythonstart.DebugEnv, "true")
+			t.Setenv(pythonstart.DebugPortEnv, "5679")
+		})
+
+		it("adds a debug process and exports the debug port", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Args:    []string{"server.py"},
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "debug",
+					Command: "python -m debugpy --listen 0.0.0.0:${DEBUG_PORT:-5679} server.py",
+				},
+			}))
+			Expect(result.Layers[0].LaunchEnv).To(HaveKeyWithValue("DEBUG_PORT.default", "5679"))
+
+			Expect(buffer.String()).To(ContainSubstring("Adding debug process for web"))
+			Expect(buffer.String()).To(ContainSubstring("Listening on port 5679 unless DEBUG_PORT is set"))
+		})
+
+		context("when the web process has its own working directory", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "src"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("web: python app.py\n"), os.ModePerm)).To(Succeed())
+				t.Setenv(pythonstart.ProcessDirEnv, "web:src")
+			})
+
+			it("runs the debug process in the same directory", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(Equal([]packit.Process{
+					{
+						Type:             "web",
+						Command:          "python",
+						Args:             []string{"app.py"},
+						Default:          true,
+						Direct:           true,
+						WorkingDirectory: filepath.Join(workingDir, "src"),
+					},
+					{
+						Type:             "debug",
+						Command:          "python -m debugpy --listen 0.0.0.0:${DEBUG_PORT:-5679} app.py",
+						WorkingDirectory: filepath.Join(workingDir, "src"),
+					},
+				}))
+			})
+		})
+
+		context("when the web process has its own launch environment", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.ProcessEnvEnv, "web:GREETING=hello web:MODE=web debug:MODE=debug")
+			})
+
+			it("gives the debug process the same environment", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Layers[1].ProcessLaunchEnv).To(Equal(map[string]packit.Environment{
+					"web": {
+						"GREETING.override": "hello",
+						"MODE.override":     "web",
+					},
+					"debug": {
+						"GREETING.override": "hello",
+						"MODE.override":     "debug",
+					},
+				}))
+			})
+		})
+
+		context("when the web process starts the Python REPL", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "server.py"))).To(Succeed())
+			})
+
+			it("does not add a debug process", func() {
+				result, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(result.Launch.Processes).To(HaveLen(1))
+				Expect(buffer.String()).To(ContainSubstring("Skipping debug process: the web process does not run a Python script, module or known server"))
+			})
+		})
+	})
+
+	context("when process settings are configured", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(workingDir, "jobs"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("web: python server.py\nworker: python worker.py\n"), os.ModePerm)).To(Succeed())
+			t.Setenv(pythonstart.ProcessEnvEnv, `web:GREETING="hello world" worker:QUEUE=default worker:CONCURRENCY=4`)
+			t.Setenv(pythonstart.ProcessDirEnv, "worker:jobs")
+		})
+
+		it("writes the launch environment of each process type and sets its working directory", func() {
+			result, err := build(packit.BuildContext{
+				WorkingDir: workingDir,
+				Layers:     packit.Layers{Path: layersDir},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.Launch.Processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "python",
+					Args:    []string{"server.py"},
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:             "worker",
+					Command:          "python",
+					Args:             []string{"worker.py"},
+					Direct:           true,
+					WorkingDirectory: filepath.Join(workingDir, "jobs"),
+				},
+			}))
+
+			Expect(result.Layers).To(HaveLen(2))
+			Expect(result.Layers[0].Name).To(Equal(pythonstart.LaunchEnvLayer))
+			layer := result.Layers[1]
+			Expect(layer.Name).To(Equal(pythonstart.ProcessEnvLayer))
+			Expect(layer.Path).To(Equal(filepath.Join(layersDir, pythonstart.ProcessEnvLayer)))
+			Expect(layer.Launch).To(BeTrue())
+			Expect(layer.Build).To(BeFalse())
+			Expect(layer.ProcessLaunchEnv).To(Equal(map[string]packit.Environment{
+				"web": {
+					"GREETING.override": "hello world",
+				},
+				"worker": {
+					"QUEUE.override":       "default",
+					"CONCURRENCY.override": "4",
+				},
+			}))
+
+			Expect(buffer.String()).To(ContainSubstring("web (default): python server.py"))
+			Expect(buffer.String()).To(ContainSubstring(`GREETING -> "hello world"`))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the Procfile is malformed", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("not a process\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to parse Procfile line 1")))
+			})
+		})
+
+		context("when a lock file is malformed", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "poetry.lock"), []byte("[[[\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the error that fails detection", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to read dependencies from poetry.lock: failed to parse poetry.lock")))
+
+				var malformed pythonstart.MalformedFileError
+				Expect(errors.As(err, &malformed)).To(BeTrue())
+			})
+		})
+
+		context("when BP_PYTHON_START_COMMAND is empty", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.StartCommandEnv, "  ")
+			})
+
+			it("returns an error", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_START_COMMAND value "  ": empty command`)))
+			})
+		})
+
+		context("when BP_PYTHON_START_COMMAND has unbalanced quotes", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.StartCommandEnv, `python "app.py`)
+			})
+
+			it("returns an error", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_START_COMMAND value "python \"app.py": invalid command`)))
+			})
+		})
+
+		context("when BP_PYTHON_DEFAULT_PROCESS does not match a process type", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.DefaultProcessEnv, "worker")
+			})
+
+			it("returns an error", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError(`failed to set default process: "worker" does not match any process type (web)`))
+			})
+		})
+
+		context("when BP_PYTHON_PROCESS_ENV does not match a process type", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.ProcessEnvEnv, "worker:QUEUE=default")
+			})
+
+			it("returns an error", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError(`failed to apply BP_PYTHON_PROCESS_ENV: "worker" does not match any process type (web)`))
+			})
+		})
+
+		context("when BP_PYTHON_PROCESS_WORKING_DIRECTORY does not match a process type", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.ProcessDirEnv, "worker:jobs")
+			})
+
+			it("returns an error", func() {
+				_, err := build(packit.BuildContext{
+					WorkingDir: workingDir,
+					Layers:     packit.Layers{Path: layersDir},
+				})
+				Expect(err).To(MatchError(`failed to apply BP_PYTHON_PROCESS_WORKING_DIRECTORY: "worker" does not match any process type (web)`))
+			})
+		})
+	})
 }
@@ -0,0 +1,896 @@
+package pythonstart
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mattn/go-shellwords"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+const (
+	LiveReloadEnv        = "BP_LIVE_RELOAD_ENABLED"
+	ReloadPathsEnv       = "BP_PYTHON_RELOAD_WATCH_PATHS"
+	ReloadIgnoreEnv      = "BP_PYTHON_RELOAD_IGNORE"
+	ReloadDebounceEnv    = "BP_PYTHON_RELOAD_DEBOUNCE"
+	ReloadSignalEnv      = "BP_PYTHON_RELOAD_SIGNAL"
+	DebugEnv             = "BP_PYTHON_DEBUG_ENABLED"
+	DebugPortEnv         = "BP_PYTHON_DEBUG_PORT"
+	DebugWaitEnv         = "BP_PYTHON_DEBUG_WAIT_FOR_CLIENT"
+	NotebookServerEnv    = "BP_PYTHON_NOTEBOOK_SERVER"
+	NotebookTokenEnv     = "BP_PYTHON_NOTEBOOK_TOKEN"
+	NotebookEnv          = "BP_PYTHON_NOTEBOOK"
+	NotebookExecutorEnv  = "BP_PYTHON_NOTEBOOK_EXECUTOR"
+	WorkerConcurrencyEnv = "BP_PYTHON_WORKER_CONCURRENCY"
+	PackageManagersEnv   = "BP_ENABLE_PACKAGE_MANAGERS"
+	StartCommandEnv      = "BP_PYTHON_START_COMMAND"
+	WSGIAppEnv           = "BP_PYTHON_WSGI_APP"
+	ASGIAppEnv           = "BP_PYTHON_ASGI_APP"
+	DefaultPortEnv       = "BP_PYTHON_DEFAULT_PORT"
+	DefaultProcessEnv    = "BP_PYTHON_DEFAULT_PROCESS"
+	AppRootEnv           = "BP_PYTHON_APP_ROOT"
+	SourceDepthEnv       = "BP_PYTHON_SOURCE_DEPTH"
+	DetectionReportEnv   = "BP_PYTHON_DETECTION_REPORT"
+	PermissivePlansEnv   = "BP_PYTHON_PERMISSIVE_PLANS"
+	ProcessEnvEnv        = "BP_PYTHON_PROCESS_ENV"
+	ProcessDirEnv        = "BP_PYTHON_PROCESS_WORKING_DIRECTORY"
+)
+
+// Configuration holds the validated buildpack settings.
+type Configuration struct {
+	LiveReloadEnabled      bool
+	PackageManagersEnabled bool
+
+	// ReloadWatchPaths are the directories, relative to the application root,
+	// watched by the reload process.
+	ReloadWatchPaths []string
+
+	// ReloadIgnore holds the glob patterns of the files whose changes do not
+	// restart the reload process.
+	ReloadIgnore []string
+
+	// ReloadDebounce is how long the reload process waits for further changes
+	// before restarting.
+	ReloadDebounce time.Duration
+
+	// ReloadSignal is the signal that stops the reloaded process.
+	ReloadSignal string
+
+	// DebugEnabled adds a debug process that runs the web process under
+	// debugpy.
+	DebugEnabled bool
+
+	// DebugPort is the port debugpy listens on when $DEBUG_PORT is not set.
+	DebugPort int
+
+	// DebugWaitForClient holds the debug process until a debugger attaches.
+	DebugWaitForClient bool
+
+	// StartCommand is the command line of the web process. It is empty when
+	// the web process should be inferred.
+	StartCommand string
+
+	// WSGIApp and ASGIApp are module:callable references that override
+	// application discovery. They are empty when discovery should run.
+	WSGIApp string
+	ASGIApp string
+
+	// DefaultPort is the port web processes listen on when the platform does
+	// not set $PORT.
+	DefaultPort int
+
+	// DefaultProcess is the process type to mark as the default. It is empty
+	// when the default should be chosen by the buildpack.
+	DefaultProcess string
+
+	// AppRoot is the directory of the application relative to the workspace.
+	// It is empty when the application is at the root of the workspace.
+	AppRoot string
+
+	// SourceDepth is the number of directories below the application root
+	// searched for Python sources during detection.
+	SourceDepth int
+
+	// DetectionReportEnabled writes the detection report as JSON to the
+	// layers directory.
+	DetectionReportEnabled bool
+
+	// PermissivePlans offers every package manager plan during detection,
+	// whether or not its files are present.
+	PermissivePlans bool
+
+	// NotebookServer is the server of the web process of a notebook project,
+	// jupyterlab or voila. It is empty when the server should be chosen from
+	// the declared dependencies.
+	NotebookServer string
+
+	// NotebookToken is the token policy of the notebook server, generate or
+	// none.
+	NotebookToken string
+
+	// Notebook is the notebook, relative to the application root, executed by
+	// the notebook process. It is empty when there is no notebook process.
+	Notebook string
+
+	// NotebookExecutor runs the notebook process, papermill or nbconvert. It
+	// is empty when the executor should be chosen from the declared
+	// dependencies.
+	NotebookExecutor string
+
+	// WorkerConcurrency is the number of tasks a worker process runs at
+	// once. It is zero when the task queue default should be used.
+	WorkerConcurrency int
+
+	// ProcessEnv holds the launch environment variables of each process type.
+	ProcessEnv map[string]map[string]string
+
+	// ProcessWorkingDirectories holds the working directory of each process
+	// type, relative to the application root.
+	ProcessWorkingDirectories map[string]string
+
+	// Settings lists the effective value of every option.
+	Settings []Setting
+}
+
+// OptionType describes the kind of value a configuration option accepts.
+type OptionType string
+
+const (
+	BoolOption         OptionType = "bool"
+	CommandOption      OptionType = "command"
+	AppReferenceOption OptionType = "module:callable"
+	ProcessTypeOption  OptionType = "process type"
+	PathOption         OptionType = "path"
+	IntegerOption      OptionType = "integer"
+	PortOption         OptionType = "port"
+	PathListOption     OptionType = "path list"
+	GlobListOption     OptionType = "glob list"
+	DurationOption     OptionType = "duration"
+	SignalOption       OptionType = "signal"
+	ChoiceOption       OptionType = "choice"
+	ProcessEnvOption   OptionType = "process:NAME=value list"
+	ProcessPathOption  OptionType = "process:path list"
+)
+
+// ConfigurationOption declares a setting understood by the buildpack. Name is
+// the environment variable and Key is the key in the [tool.paketo.python-start]
+// table of pyproject.toml.
+type ConfigurationOption struct {
+	Name        string
+	Key         string
+	Type        OptionType
+	Default     string
+	Description string
+
+	apply func(config *Configuration, value string) error
+}
+
+// Setting is the effective value of a configuration option along with where
+// that value came from.
+type Setting struct {
+	Name   string `toml:"name" json:"name"`
+	Value  string `toml:"value" json:"value"`
+	Source string `toml:"source" json:"source"`
+}
+
+// ConfigurationOptions declares every setting understood by the buildpack.
+var ConfigurationOptions = []ConfigurationOption{
+	{
+		Name:        LiveReloadEnv,
+		Key:         "live-reload-enabled",
+		Type:        BoolOption,
+		Default:     "false",
+		Description: "Add a reload process that restarts the app with watchexec when its files change",
+		apply: boolOption(func(c *Configuration) *bool {
+			return &c.LiveReloadEnabled
+		}),
+	},
+	{
+		Name:        ReloadPathsEnv,
+		Key:         "reload-watch-paths",
+		Type:        PathListOption,
+		Default:     ".",
+		Description: "Directories watched by the reload process relative to the application root",
+		apply: func(c *Configuration, value string) error {
+			paths, err := shellwords.Parse(value)
+			if err != nil {
+				return fmt.Errorf("invalid list: %w", err)
+			}
+			if len(paths) == 0 {
+				return errors.New("expected at least one path")
+			}
+
+			c.ReloadWatchPaths = nil
+			for _, path := range paths {
+				path, err = cleanRelativePath(path, "the application root")
+				if err != nil {
+					return err
+				}
+				c.ReloadWatchPaths = append(c.ReloadWatchPaths, path)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        ReloadIgnoreEnv,
+		Key:         "reload-ignore",
+		Type:        GlobListOption,
+		Default:     "__pycache__ *.pyc .venv",
+		Description: "Files whose changes do not restart the reload process",
+		apply: func(c *Configuration, value string) error {
+			patterns, err := shellwords.Parse(value)
+			if err != nil {
+				return fmt.Errorf("invalid list: %w", err)
+			}
+			for _, pattern := range patterns {
+				_, err := filepath.Match(pattern, "")
+				if err != nil {
+					return fmt.Errorf("invalid glob %q", pattern)
+				}
+			}
+			c.ReloadIgnore = patterns
+			return nil
+		},
+	},
+	{
+		Name:        ReloadDebounceEnv,
+		Key:         "reload-debounce",
+		Type:        DurationOption,
+		Default:     "500ms",
+		Description: "Time the reload process waits for further changes before restarting",
+		apply: func(c *Configuration, value string) error {
+			debounce, err := time.ParseDuration(value)
+			if err != nil || debounce < time.Millisecond {
+				return errors.New("expected a duration of at least 1ms, such as 500ms")
+			}
+			c.ReloadDebounce = debounce
+			return nil
+		},
+	},
+	{
+		Name:        ReloadSignalEnv,
+		Key:         "reload-signal",
+		Type:        SignalOption,
+		Default:     "SIGTERM",
+		Description: "Signal that stops the app before the reload process restarts it",
+		apply: func(c *Configuration, value string) error {
+			signal := strings.ToUpper(value)
+			if !strings.HasPrefix(signal, "SIG") {
+				signal = "SIG" + signal
+			}
+			if !stopSignals[signal] {
+				return errors.New("expected one of SIGHUP, SIGINT, SIGQUIT, SIGTERM, SIGKILL, SIGUSR1 or SIGUSR2")
+			}
+			c.ReloadSignal = signal
+			return nil
+		},
+	},
+	{
+		Name:        DebugEnv,
+		Key:         "debug-enabled",
+		Type:        BoolOption,
+		Default:     "false",
+		Description: "Add a debug process that runs the web process under debugpy",
+		apply: boolOption(func(c *Configuration) *bool {
+			return &c.DebugEnabled
+		}),
+	},
+	{
+		Name:        DebugPortEnv,
+		Key:         "debug-port",
+		Type:        PortOption,
+		Default:     "5678",
+		Description: "Port debugpy listens on when $DEBUG_PORT is not set",
+		apply: portOption(func(c *Configuration) *int {
+			return &c.DebugPort
+		}),
+	},
+	{
+		Name:        DebugWaitEnv,
+		Key:         "debug-wait-for-client",
+		Type:        BoolOption,
+		Default:     "false",
+		Description: "Hold the debug process until a debugger attaches",
+		apply: boolOption(func(c *Configuration) *bool {
+			return &c.DebugWaitForClient
+		}),
+	},
+	{
+		Name:        PackageManagersEnv,
+		Key:         "enable-package-managers",
+		Type:        BoolOption,
+		Default:     "false",
+		Description: "Require package managers to be available at launch",
+		apply: boolOption(func(c *Configuration) *bool {
+			return &c.PackageManagersEnabled
+		}),
+	},
+	{
+		Name:        StartCommandEnv,
+		Key:         "start-command",
+		Type:        CommandOption,
+		Description: "Command line run by the web process",
+		apply: func(c *Configuration, value string) error {
+			_, err := newProcess("web", value)
+			if err != nil {
+				return err
+			}
+			c.StartCommand = value
+			return nil
+		},
+	},
+	{
+		Name:        WSGIAppEnv,
+		Key:         "wsgi-app",
+		Type:        AppReferenceOption,
+		Description: "WSGI callable served by gunicorn",
+		apply: appReferenceOption(func(c *Configuration) *string {
+			return &c.WSGIApp
+		}),
+	},
+	{
+		Name:        ASGIAppEnv,
+		Key:         "asgi-app",
+		Type:        AppReferenceOption,
+		Description: "ASGI application served by an ASGI server",
+		apply: appReferenceOption(func(c *Configuration) *string {
+			return &c.ASGIApp
+		}),
+	},
+	{
+		Name:        DefaultPortEnv,
+		Key:         "default-port",
+		Type:        PortOption,
+		Default:     "8080",
+		Description: "Port web processes listen on when $PORT is not set",
+		apply: portOption(func(c *Configuration) *int {
+			return &c.DefaultPort
+		}),
+	},
+	{
+		Name:        DefaultProcessEnv,
+		Key:         "default-process",
+		Type:        ProcessTypeOption,
+		Description: "Process type launched by default",
+		apply: func(c *Configuration, value string) error {
+			if value != "" && !processTypePattern.MatchString(value) {
+				return errors.New("expected only letters, digits, '.', '_' and '-'")
+			}
+			c.DefaultProcess = value
+			return nil
+		},
+	},
+	{
+		Name:        AppRootEnv,
+		Key:         "app-root",
+		Type:        PathOption,
+		Description: "Directory of the application relative to the workspace",
+		apply: func(c *Configuration, value string) error {
+			root, err := cleanRelativePath(value, "the workspace")
+			if err != nil {
+				return err
+			}
+			if root != "." {
+				c.AppRoot = root
+			}
+			return nil
+		},
+	},
+	{
+		Name:        SourceDepthEnv,
+		Key:         "source-depth",
+		Type:        IntegerOption,
+		Default:     "3",
+		Description: "Directory depth searched for *.py files during detection",
+		apply: func(c *Configuration, value string) error {
+			depth, err := strconv.Atoi(value)
+			if err != nil || depth < 0 {
+				return errors.New("expected a non-negative integer")
+			}
+			c.SourceDepth = depth
+			return nil
+		},
+	},
+	{
+		Name:        DetectionReportEnv,
+		Key:         "detection-report",
+		Type:        BoolOption,
+		Default:     "false",
+		Description: "Write the detection report as JSON to the layers directory",
+		apply: boolOption(func(c *Configuration) *bool {
+			return &c.DetectionReportEnabled
+		}),
+	},
+	{
+		Name:        PermissivePlansEnv,
+		Key:         "permissive-plans",
+		Type:        BoolOption,
+		Default:     "false",
+		Description: "Offer every package manager plan regardless of the files present",
+		apply: boolOption(func(c *Configuration) *bool {
+			return &c.PermissivePlans
+		}),
+	},
+	{
+		Name:        NotebookServerEnv,
+		Key:         "notebook-server",
+		Type:        ChoiceOption,
+		Description: "Server of the web process of a notebook project, jupyterlab or voila",
+		apply: choiceOption(func(c *Configuration) *string {
+			return &c.NotebookServer
+		}, NotebookServers...),
+	},
+	{
+		Name:        NotebookTokenEnv,
+		Key:         "notebook-token",
+		Type:        ChoiceOption,
+		Default:     "generate",
+		Description: "Token policy of the notebook server, generate or none",
+		apply: choiceOption(func(c *Configuration) *string {
+			return &c.NotebookToken
+		}, NotebookTokenPolicies...),
+	},
+	{
+		Name:        NotebookEnv,
+		Key:         "notebook",
+		Type:        PathOption,
+		Description: "Notebook executed by the notebook process relative to the application root",
+		apply: func(c *Configuration, value string) error {
+			notebook, err := cleanRelativePath(value, "the application root")
+			if err != nil {
+				return err
+			}
+			if filepath.Ext(notebook) != ".ipynb" {
+				return errors.New("expected a path to an .ipynb file")
+			}
+			c.Notebook = notebook
+			return nil
+		},
+	},
+	{
+		Name:        NotebookExecutorEnv,
+		Key:         "notebook-executor",
+		Type:        ChoiceOption,
+		Description: "Executor of the notebook process, papermill or nbconvert",
+		apply: choiceOption(func(c *Configuration) *string {
+			return &c.NotebookExecutor
+		}, NotebookExecutors...),
+	},
+	{
+		Name:        WorkerConcurrencyEnv,
+		Key:         "worker-concurrency",
+		Type:        IntegerOption,
+		Description: "Number of tasks each worker process runs at once",
+		apply: func(c *Configuration, value string) error {
+			if value == "" {
+				c.WorkerConcurrency = 0
+				return nil
+			}
+			concurrency, err := strconv.Atoi(value)
+			if err != nil || concurrency < 1 {
+				return errors.New("expected a positive integer")
+			}
+			c.WorkerConcurrency = concurrency
+			return nil
+		},
+	},
+	{
+		Name:        ProcessEnvEnv,
+		Key:         "process-env",
+		Type:        ProcessEnvOption,
+		Description: "Launch environment variables of each process type",
+		apply: func(c *Configuration, value string) error {
+			entries, err := parseProcessEntries(value)
+			if err != nil {
+				return err
+			}
+
+			c.ProcessEnv = map[string]map[string]string{}
+			for _, entry := range entries {
+				name, envValue, ok := strings.Cut(entry.value, "=")
+				if !ok || !envNamePattern.MatchString(name) {
+					return fmt.Errorf("expected <process type>:<NAME>=<value>, got %q", entry.processType+":"+entry.value)
+				}
+				if c.ProcessEnv[entry.processType] == nil {
+					c.ProcessEnv[entry.processType] = map[string]string{}
+				}
+				c.ProcessEnv[entry.processType][name] = envValue
+			}
+			return nil
+		},
+	},
+	{
+		Name:        ProcessDirEnv,
+		Key:         "process-working-directory",
+		Type:        ProcessPathOption,
+		Description: "Working directory of each process type relative to the application root",
+		apply: func(c *Configuration, value string) error {
+			entries, err := parseProcessEntries(value)
+			if err != nil {
+				return err
+			}
+
+			c.ProcessWorkingDirectories = map[string]string{}
+			for _, entry := range entries {
+				dir, err := cleanRelativePath(entry.value, "the application root")
+				if err != nil {
+					return fmt.Errorf("invalid working directory for %s: %w", entry.processType, err)
+				}
+				c.ProcessWorkingDirectories[entry.processType] = dir
+			}
+			return nil
+		},
+	},
+}
+
+// stopSignals are the signals the reload process may stop the app with.
+var stopSignals = map[string]bool{
+	"SIGHUP":  true,
+	"SIGINT":  true,
+	"SIGQUIT": true,
+	"SIGTERM": true,
+	"SIGKILL": true,
+	"SIGUSR1": true,
+	"SIGUSR2": true,
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type processEntry struct {
+	processType string
+	value       string
+}
+
+// parseProcessEntries splits a list of <process type>:<value> entries, which
+// are separated by whitespace and may be quoted using shell quoting rules.
+func parseProcessEntries(value string) ([]processEntry, error) {
+	words, err := shellwords.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid list: %w", err)
+	}
+
+	var entries []processEntry
+	for _, word := range words {
+		processType, entryValue, ok := strings.Cut(word, ":")
+		if !ok || !processTypePattern.MatchString(processType) {
+			return nil, fmt.Errorf("expected <process type>:<value>, got %q", word)
+		}
+		entries = append(entries, processEntry{processType: processType, value: entryValue})
+	}
+
+	return entries, nil
+}
+
+// cleanRelativePath returns the given path cleaned, failing when it is
+// absolute or leaves the directory it is relative to.
+func cleanRelativePath(value, base string) (string, error) {
+	if filepath.IsAbs(value) {
+		return "", fmt.Errorf("expected a path relative to %s", base)
+	}
+	cleaned := filepath.Clean(value)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("expected a path inside %s", base)
+	}
+	return cleaned, nil
+}
+
+// ConfigurationLoader reads the buildpack settings from the build environment
+// and the application source.
+type ConfigurationLoader struct {
+	lookupEnv func(string) (string, bool)
+}
+
+// NewConfigurationLoader returns a ConfigurationLoader that reads the process
+// environment.
+func NewConfigurationLoader() ConfigurationLoader {
+	return ConfigurationLoader{
+		lookupEnv: os.LookupEnv,
+	}
+}
+
+// Load reads and validates every configuration option. Each option is read,
+// in order of precedence, from the environment, the [tool.paketo.python-start]
+// table of pyproject.toml and the build environment variables declared in the
+// project.toml of the given directory, before falling back to the option
+// default. The application root is resolved first, as the pyproject.toml is
+// read from it. All invalid values are reported together in the returned
+// error.
+func (l ConfigurationLoader) Load(workingDir string) (Configuration, error) {
+	var (
+		config Configuration
+		errs   []error
+	)
+
+	workspaceValues, err := readPyprojectSettings(filepath.Join(workingDir, "pyproject.toml"), "pyproject.toml")
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	projectValues, err := readProjectDescriptorEnv(filepath.Join(workingDir, "project.toml"))
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	lookup := func(option ConfigurationOption, pyprojectValues map[string]string, pyprojectFile string) (string, string) {
+		value, source := option.Default, "default"
+		if v, ok := projectValues[option.Name]; ok {
+			value, source = v, "project.toml"
+		}
+		if v, ok := pyprojectValues[option.Key]; ok {
+			value, source = v, pyprojectFile
+		}
+		if v, ok := l.lookupEnv(option.Name); ok {
+			value, source = v, "environment"
+		}
+		return value, source
+	}
+
+	// The application root can only be set at the root of the workspace. An
+	// invalid value is reported with the other options below.
+	pyprojectValues, pyprojectFile := workspaceValues, "pyproject.toml"
+	for _, option := range ConfigurationOptions {
+		if option.Name != AppRootEnv {
+			continue
+		}
+
+		var root Configuration
+		value, _ := lookup(option, workspaceValues, pyprojectFile)
+		if option.apply(&root, value) != nil || root.AppRoot == "" {
+			break
+		}
+
+		appDir, err := root.AppDir(workingDir)
+		if err != nil {
+			return Configuration{}, err
+		}
+
+		pyprojectFile = filepath.Join(root.AppRoot, "pyproject.toml")
+		pyprojectValues, err = readPyprojectSettings(filepath.Join(appDir, "pyproject.toml"), pyprojectFile)
+		if err != nil {
+			return Configuration{}, err
+		}
+
+		var ignored []string
+		for key := range workspaceValues {
+			if key != option.Key {
+				ignored = append(ignored, key)
+			}
+		}
+		sort.Strings(ignored)
+		for _, key := range ignored {
+			errs = append(errs, fmt.Errorf("key %q in [tool.paketo.python-start] of pyproject.toml is ignored when %s is set, move it to %s", key, AppRootEnv, pyprojectFile))
+		}
+		if _, ok := pyprojectValues[option.Key]; ok {
+			errs = append(errs, fmt.Errorf("key %q in [tool.paketo.python-start] of %s is only read from the pyproject.toml at the root of the workspace", option.Key, pyprojectFile))
+		}
+	}
+
+	known := map[string]bool{}
+	for _, option := range ConfigurationOptions {
+		known[option.Key] = true
+	}
+
+	var unknown []string
+	for key := range pyprojectValues {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("unknown key %q in [tool.paketo.python-start] of %s", key, pyprojectFile))
+	}
+
+	for _, option := range ConfigurationOptions {
+		var value, source string
+		if option.Name == AppRootEnv {
+			value, source = lookup(option, workspaceValues, "pyproject.toml")
+		} else {
+			value, source = lookup(option, pyprojectValues, pyprojectFile)
+		}
+
+		if source == "default" && value == "" {
+			config.Settings = append(config.Settings, Setting{Name: option.Name, Source: source})
+			continue
+		}
+
+		err := option.apply(&config, value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %s value %q: %w", option.Name, value, err))
+			continue
+		}
+
+		config.Settings = append(config.Settings, Setting{Name: option.Name, Value: value, Source: source})
+	}
+
+	if len(errs) > 0 {
+		return Configuration{}, fmt.Errorf("invalid buildpack configuration:\n%w", errors.Join(errs...))
+	}
+
+	return config, nil
+}
+
+// AppDir returns the directory of the application within the given
+// workspace. It fails when the directory does not exist or resolves, through
+// symbolic links, to a location outside the workspace.
+func (c Configuration) AppDir(workingDir string) (string, error) {
+	if c.AppRoot == "" {
+		return workingDir, nil
+	}
+
+	dir := filepath.Join(workingDir, c.AppRoot)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to find %s directory: %w", AppRootEnv, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("failed to find %s directory: %s is not a directory", AppRootEnv, c.AppRoot)
+	}
+
+	workspace, err := filepath.EvalSymlinks(workingDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve workspace: %w", err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s directory: %w", AppRootEnv, err)
+	}
+
+	rel, err := filepath.Rel(workspace, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("failed to resolve %s directory: %s is outside of the workspace", AppRootEnv, c.AppRoot)
+	}
+
+	return dir, nil
+}
+
+// Log prints a table of the effective settings.
+func (c Configuration) Log(logger scribe.Emitter) {
+	width := 0
+	for _, setting := range c.Settings {
+		if len(setting.Name) > width {
+			width = len(setting.Name)
+		}
+	}
+
+	logger.Process("Build configuration:")
+	for _, setting := range c.Settings {
+		logger.Subprocess("%-*s -> %q (%s)", width, setting.Name, setting.Value, setting.Source)
+	}
+	logger.Break()
+}
+
+// readPyprojectSettings returns the values of the [tool.paketo.python-start]
+// table of the given pyproject.toml, which errors refer to by the given name.
+func readPyprojectSettings(path, name string) (map[string]string, error) {
+	var pyproject struct {
+		Tool struct {
+			Paketo map[string]map[string]interface{} `toml:"paketo"`
+		} `toml:"tool"`
+	}
+	_, err := toml.DecodeFile(path, &pyproject)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, malformedFileError(name, err)
+	}
+
+	values := map[string]string{}
+	for key, value := range pyproject.Tool.Paketo["python-start"] {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		case int64:
+			values[key] = strconv.FormatInt(v, 10)
+		case []interface{}:
+			// Lists hold one entry each and are joined with shell quoting.
+			var entries []string
+			for _, item := range v {
+				entry, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("failed to parse %s: unsupported value for %q in [tool.paketo.python-start]", name, key)
+				}
+				entries = append(entries, shellQuote(entry))
+			}
+			values[key] = strings.Join(entries, " ")
+		default:
+			return nil, fmt.Errorf("failed to parse %s: unsupported value for %q in [tool.paketo.python-start]", name, key)
+		}
+	}
+
+	return values, nil
+}
+
+// readProjectDescriptorEnv returns the build environment variables declared
+// in the given project.toml, supporting both the [[io.buildpacks.build.env]]
+// and the legacy [[build.env]] tables.
+func readProjectDescriptorEnv(path string) (map[string]string, error) {
+	type envVar struct {
+		Name  string `toml:"name"`
+		Value string `toml:"value"`
+	}
+
+	var descriptor struct {
+		Build struct {
+			Env []envVar `toml:"env"`
+		} `toml:"build"`
+		IO struct {
+			Buildpacks struct {
+				Build struct {
+					Env []envVar `toml:"env"`
+				} `toml:"build"`
+			} `toml:"buildpacks"`
+		} `toml:"io"`
+	}
+	_, err := toml.DecodeFile(path, &descriptor)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse project.toml: %w", err)
+	}
+
+	values := map[string]string{}
+	for _, env := range append(descriptor.Build.Env, descriptor.IO.Buildpacks.Build.Env...) {
+		values[env.Name] = env.Value
+	}
+
+	return values, nil
+}
+
+func boolOption(field func(*Configuration) *bool) func(*Configuration, string) error {
+	return func(c *Configuration, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected true or false")
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+func portOption(field func(*Configuration) *int) func(*Configuration, string) error {
+	return func(c *Configuration, value string) error {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return errors.New("expected a port number between 1 and 65535")
+		}
+		*field(c) = port
+		return nil
+	}
+}
+
+func choiceOption(field func(*Configuration) *string, choices ...string) func(*Configuration, string) error {
+	return func(c *Configuration, value string) error {
+		for _, choice := range choices {
+			if value == choice {
+				*field(c) = value
+				return nil
+			}
+		}
+		return fmt.Errorf("expected one of %s", strings.Join(choices, ", "))
+	}
+}
+
+func appReferenceOption(field func(*Configuration) *string) func(*Configuration, string) error {
+	return func(c *Configuration, value string) error {
+		_, err := parseAppReference(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = value
+		return nil
+	}
+}
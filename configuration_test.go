@@ -0,0 +1,421 @@
+package pythonstart_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testConfiguration(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		loader     pythonstart.ConfigurationLoader
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		loader = pythonstart.NewConfigurationLoader()
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("ConfigurationOptions", func() {
+		it("declares a type and description for every option", func() {
+			for _, option := range pythonstart.ConfigurationOptions {
+				Expect(option.Name).To(HavePrefix("BP_"))
+				Expect(option.Type).NotTo(BeEmpty())
+				Expect(option.Description).NotTo(BeEmpty())
+			}
+		})
+	})
+
+	context("Load", func() {
+		context("when nothing is set", func() {
+			it("returns the defaults", func() {
+				config, err := loader.Load(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.LiveReloadEnabled).To(BeFalse())
+				Expect(config.PackageManagersEnabled).To(BeFalse())
+				Expect(config.StartCommand).To(BeEmpty())
+				Expect(config.DefaultPort).To(Equal(8080))
+				Expect(config.ReloadWatchPaths).To(Equal([]string{"."}))
+				Expect(config.ReloadIgnore).To(Equal([]string{"__pycache__", "*.pyc", ".venv"}))
+				Expect(config.ReloadDebounce).To(Equal(500 * time.Millisecond))
+				Expect(config.ReloadSignal).To(Equal("SIGTERM"))
+				Expect(config.DebugEnabled).To(BeFalse())
+				Expect(config.DebugPort).To(Equal(5678))
+				Expect(config.NotebookServer).To(BeEmpty())
+				Expect(config.NotebookToken).To(Equal("generate"))
+				Expect(config.WorkerConcurrency).To(BeZero())
+				Expect(config.Settings).To(ContainElements(
+					pythonstart.Setting{Name: pythonstart.LiveReloadEnv, Value: "false", Source: "default"},
+					pythonstart.Setting{Name: pythonstart.StartCommandEnv, Source: "default"},
+				))
+				Expect(config.Settings).To(HaveLen(len(pythonstart.ConfigurationOptions)))
+			})
+		})
+
+		context("when values are set in the environment", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.LiveReloadEnv, "true")
+				t.Setenv(pythonstart.PackageManagersEnv, "1")
+				t.Setenv(pythonstart.StartCommandEnv, "python app.py")
+				t.Setenv(pythonstart.WSGIAppEnv, "app:application")
+				t.Setenv(pythonstart.ASGIAppEnv, "main:app")
+				t.Setenv(pythonstart.DefaultProcessEnv, "worker")
+			})
+
+			it("returns the parsed values", func() {
+				config, err := loader.Load(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.LiveReloadEnabled).To(BeTrue())
+				Expect(config.PackageManagersEnabled).To(BeTrue())
+				Expect(config.StartCommand).To(Equal("python app.py"))
+				Expect(config.WSGIApp).To(Equal("app:application"))
+				Expect(config.ASGIApp).To(Equal("main:app"))
+				Expect(config.DefaultProcess).To(Equal("worker"))
+				Expect(config.Settings).To(ContainElement(
+					pythonstart.Setting{Name: pythonstart.PackageManagersEnv, Value: "1", Source: "environment"},
+				))
+			})
+		})
+
+		context("when values are set in pyproject.toml", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[project]
+name = "app"
+
+[tool.paketo.python-start]
+live-reload-enabled = true
+start-command = "python app.py"
+default-process = "worker"
+`), os.ModePerm)).To(Succeed())
+				t.Setenv(pythonstart.DefaultProcessEnv, "web")
+			})
+
+			it("returns the values with the environment taking precedence", func() {
+				config, err := loader.Load(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.LiveReloadEnabled).To(BeTrue())
+				Expect(config.StartCommand).To(Equal("python app.py"))
+				Expect(config.DefaultProcess).To(Equal("web"))
+				Expect(config.Settings).To(ContainElements(
+					pythonstart.Setting{Name: pythonstart.LiveReloadEnv, Value: "true", Source: "pyproject.toml"},
+					pythonstart.Setting{Name: pythonstart.StartCommandEnv, Value: "python app.py", Source: "pyproject.toml"},
+					pythonstart.Setting{Name: pythonstart.DefaultProcessEnv, Value: "web", Source: "environment"},
+				))
+			})
+		})
+
+		context("when values are set in project.toml", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "project.toml"), []byte(`
+[[io.buildpacks.build.env]]
+name = "BP_PYTHON_WSGI_APP"
+value = "service.wsgi:app"
+
+[[io.buildpacks.build.env]]
+name = "BP_PYTHON_START_COMMAND"
+value = "python other.py"
+`), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+start-command = "python app.py"
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("uses them when pyproject.toml does not set the value", func() {
+				config, err := loader.Load(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.WSGIApp).To(Equal("service.wsgi:app"))
+				Expect(config.StartCommand).To(Equal("python app.py"))
+				Expect(config.Settings).To(ContainElements(
+					pythonstart.Setting{Name: pythonstart.WSGIAppEnv, Value: "service.wsgi:app", Source: "project.toml"},
+					pythonstart.Setting{Name: pythonstart.StartCommandEnv, Value: "python app.py", Source: "pyproject.toml"},
+				))
+			})
+		})
+
+		context("when the app root is set", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(workingDir, "services", "api"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+app-root = "services/api"
+`), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "services", "api", "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+start-command = "python server.py"
+`), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "project.toml"), []byte(`
+[[io.buildpacks.build.env]]
+name = "BP_PYTHON_DEFAULT_PORT"
+value = "9000"
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("reads the other values from the pyproject.toml of the app root", func() {
+				config, err := loader.Load(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.AppRoot).To(Equal("services/api"))
+				Expect(config.StartCommand).To(Equal("python server.py"))
+				Expect(config.DefaultPort).To(Equal(9000))
+				Expect(config.Settings).To(ContainElements(
+					pythonstart.Setting{Name: pythonstart.AppRootEnv, Value: "services/api", Source: "pyproject.toml"},
+					pythonstart.Setting{Name: pythonstart.StartCommandEnv, Value: "python server.py", Source: "services/api/pyproject.toml"},
+					pythonstart.Setting{Name: pythonstart.DefaultPortEnv, Value: "9000", Source: "project.toml"},
+				))
+			})
+		})
+
+		context("when process settings are set", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+process-env = ["web:GREETING=hello world", "web:MODE=it's live", "worker:QUEUE=default"]
+`), os.ModePerm)).To(Succeed())
+				t.Setenv(pythonstart.ProcessDirEnv, "worker:jobs web:./")
+			})
+
+			it("returns the environment variables and working directories of each process type", func() {
+				config, err := loader.Load(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(config.ProcessEnv).To(Equal(map[string]map[string]string{
+					"web":    {"GREETING": "hello world", "MODE": "it's live"},
+					"worker": {"QUEUE": "default"},
+				}))
+				Expect(config.ProcessWorkingDirectories).To(Equal(map[string]string{
+					"web":    ".",
+					"worker": "jobs",
+				}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when pyproject.toml has an unknown key", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+start-comand = "python app.py"
+`), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(ContainSubstring(`unknown key "start-comand" in [tool.paketo.python-start] of pyproject.toml`)))
+				})
+			})
+
+			context("when the workspace pyproject.toml sets values besides the app root", func() {
+				it.Before(func() {
+					Expect(os.MkdirAll(filepath.Join(workingDir, "services", "api"), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+start-command = "python app.py"
+`), os.ModePerm)).To(Succeed())
+					t.Setenv(pythonstart.AppRootEnv, "services/api")
+				})
+
+				it("returns an error", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(ContainSubstring(`key "start-command" in [tool.paketo.python-start] of pyproject.toml is ignored when BP_PYTHON_APP_ROOT is set, move it to services/api/pyproject.toml`)))
+				})
+			})
+
+			context("when the pyproject.toml of the app root sets the app root", func() {
+				it.Before(func() {
+					Expect(os.MkdirAll(filepath.Join(workingDir, "services", "api"), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(workingDir, "services", "api", "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+app-root = "."
+start-comand = "python server.py"
+`), os.ModePerm)).To(Succeed())
+					t.Setenv(pythonstart.AppRootEnv, "services/api")
+				})
+
+				it("returns an error", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(ContainSubstring(`key "app-root" in [tool.paketo.python-start] of services/api/pyproject.toml is only read from the pyproject.toml at the root of the workspace`)))
+					Expect(err).To(MatchError(ContainSubstring(`unknown key "start-comand" in [tool.paketo.python-start] of services/api/pyproject.toml`)))
+				})
+			})
+
+			context("when the app root does not exist", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.AppRootEnv, "services/api")
+				})
+
+				it("returns an error", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to find BP_PYTHON_APP_ROOT directory")))
+				})
+			})
+
+			context("when pyproject.toml has a value of an unsupported type", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.paketo.python-start]
+source-depth = 1.5
+`), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(`failed to parse pyproject.toml: unsupported value for "source-depth" in [tool.paketo.python-start]`))
+				})
+			})
+
+			context("when pyproject.toml is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[[[\n"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns a malformed file error", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse pyproject.toml")))
+
+					var malformed pythonstart.MalformedFileError
+					Expect(errors.As(err, &malformed)).To(BeTrue())
+					Expect(malformed.File).To(Equal("pyproject.toml"))
+				})
+			})
+
+			context("when project.toml is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "project.toml"), []byte("%%%"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse project.toml")))
+				})
+			})
+
+			context("when several values are invalid", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.LiveReloadEnv, "not-a-bool")
+					t.Setenv(pythonstart.StartCommandEnv, `python "app.py`)
+					t.Setenv(pythonstart.WSGIAppEnv, "app")
+					t.Setenv(pythonstart.DefaultProcessEnv, "my worker")
+					t.Setenv(pythonstart.AppRootEnv, "../other")
+					t.Setenv(pythonstart.SourceDepthEnv, "-1")
+					t.Setenv(pythonstart.DefaultPortEnv, "80000")
+					t.Setenv(pythonstart.ReloadPathsEnv, "src ../lib")
+					t.Setenv(pythonstart.ReloadIgnoreEnv, "[abc")
+					t.Setenv(pythonstart.ReloadDebounceEnv, "soon")
+					t.Setenv(pythonstart.ReloadSignalEnv, "SIGSTOP")
+					t.Setenv(pythonstart.DebugPortEnv, "0")
+					t.Setenv(pythonstart.NotebookServerEnv, "jupyter")
+					t.Setenv(pythonstart.NotebookEnv, "report.py")
+					t.Setenv(pythonstart.WorkerConcurrencyEnv, "0")
+					t.Setenv(pythonstart.ProcessEnvEnv, "web:1DEBUG=true")
+					t.Setenv(pythonstart.ProcessDirEnv, "worker:/jobs")
+				})
+
+				it("reports every invalid value", func() {
+					_, err := loader.Load(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("invalid buildpack configuration:")))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_LIVE_RELOAD_ENABLED value "not-a-bool": expected true or false`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_START_COMMAND value "python \"app.py": invalid command`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_WSGI_APP value "app": expected <module>:<callable>`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_DEFAULT_PROCESS value "my worker": expected only letters, digits, '.', '_' and '-'`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_APP_ROOT value "../other": expected a path inside the workspace`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_SOURCE_DEPTH value "-1": expected a non-negative integer`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_DEFAULT_PORT value "80000": expected a port number between 1 and 65535`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_RELOAD_WATCH_PATHS value "src ../lib": expected a path inside the application root`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_RELOAD_IGNORE value "[abc": invalid glob "[abc"`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_RELOAD_DEBOUNCE value "soon": expected a duration of at least 1ms, such as 500ms`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_RELOAD_SIGNAL value "SIGSTOP": expected one of SIGHUP, SIGINT, SIGQUIT, SIGTERM, SIGKILL, SIGUSR1 or SIGUSR2`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_NOTEBOOK_SERVER value "jupyter": expected one of jupyterlab, voila`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_NOTEBOOK value "report.py": expected a path to an .ipynb file`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_DEBUG_PORT value "0": expected a port number between 1 and 65535`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_WORKER_CONCURRENCY value "0": expected a positive integer`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_PROCESS_ENV value "web:1DEBUG=true": expected <process type>:<NAME>=<value>, got "web:1DEBUG=true"`)))
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_PYTHON_PROCESS_WORKING_DIRECTORY value "worker:/jobs": invalid working directory for worker: expected a path relative to the application root`)))
+				})
+			})
+		})
+	})
+
+	context("AppDir", func() {
+		it("returns the workspace when no app root is set", func() {
+			dir, err := pythonstart.Configuration{}.AppDir(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dir).To(Equal(workingDir))
+		})
+
+		it("returns the app root within the workspace", func() {
+			Expect(os.MkdirAll(filepath.Join(workingDir, "services", "api"), os.ModePerm)).To(Succeed())
+
+			dir, err := pythonstart.Configuration{AppRoot: "services/api"}.AppDir(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dir).To(Equal(filepath.Join(workingDir, "services", "api")))
+		})
+
+		context("failure cases", func() {
+			context("when the app root is a file", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.Configuration{AppRoot: "app.py"}.AppDir(workingDir)
+					Expect(err).To(MatchError("failed to find BP_PYTHON_APP_ROOT directory: app.py is not a directory"))
+				})
+			})
+
+			context("when the app root links outside of the workspace", func() {
+				var outsideDir string
+
+				it.Before(func() {
+					var err error
+					outsideDir, err = os.MkdirTemp("", "outside")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(os.Symlink(outsideDir, filepath.Join(workingDir, "api"))).To(Succeed())
+				})
+
+				it.After(func() {
+					Expect(os.RemoveAll(outsideDir)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.Configuration{AppRoot: "api"}.AppDir(workingDir)
+					Expect(err).To(MatchError("failed to resolve BP_PYTHON_APP_ROOT directory: api is outside of the workspace"))
+				})
+			})
+		})
+	})
+
+	context("Log", func() {
+		it("prints the effective settings", func() {
+			buffer := bytes.NewBuffer(nil)
+			pythonstart.Configuration{
+				Settings: []pythonstart.Setting{
+					{Name: "BP_LIVE_RELOAD_ENABLED", Value: "false", Source: "default"},
+					{Name: "BP_PYTHON_START_COMMAND", Value: "python app.py", Source: "environment"},
+				},
+			}.Log(scribe.NewEmitter(buffer))
+
+			Expect(buffer.String()).To(ContainSubstring("Build configuration:"))
+			Expect(buffer.String()).To(ContainSubstring(`BP_LIVE_RELOAD_ENABLED  -> "false" (default)`))
+			Expect(buffer.String()).To(ContainSubstring(`BP_PYTHON_START_COMMAND -> "python app.py" (environment)`))
+		})
+	})
+}
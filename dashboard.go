@@ -0,0 +1,103 @@
+package pythonstart
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// DashboardFramework describes how an application built with a dashboard
+// framework is served.
+type DashboardFramework struct {
+	Name    string
+	Package string
+	imports *regexp.Regexp
+	command string
+}
+
+// Dashboard is a script that builds a dashboard with a framework.
+type Dashboard struct {
+	Framework DashboardFramework
+	Script    string
+}
+
+// Process returns the web process that serves the dashboard headlessly on
+// $PORT, or on the given port when $PORT is not set.
+func (d Dashboard) Process(defaultPort int) packit.Process {
+	return packit.Process{
+		Type:    "web",
+		Command: fmt.Sprintf(d.Framework.command, shellQuote(d.Script), defaultPort),
+		Default: true,
+	}
+}
+
+var (
+	// dashboardFrameworks are listed in order of preference. Gradio and Dash
+	// applications start their own server, which reads its address and port
+	// from the environment.
+	dashboardFrameworks = []DashboardFramework{
+		{
+			Name:    "Streamlit",
+			Package: "streamlit",
+			imports: importPattern("streamlit"),
+			command: "streamlit run %[1]s --server.address=0.0.0.0 --server.port=${PORT:-%[2]d} --server.headless=true",
+		},
+		{
+			Name:    "Gradio",
+			Package: "gradio",
+			imports: importPattern("gradio"),
+			command: "GRADIO_SERVER_NAME=0.0.0.0 GRADIO_SERVER_PORT=${PORT:-%[2]d} python %[1]s",
+		},
+		{
+			Name:    "Panel",
+			Package: "panel",
+			imports: importPattern("panel"),
+			command: "panel serve %[1]s --address=0.0.0.0 --port=${PORT:-%[2]d} --allow-websocket-origin='*'",
+		},
+		{
+			Name:    "Dash",
+			Package: "dash",
+			imports: importPattern("dash"),
+			command: "HOST=0.0.0.0 PORT=${PORT:-%[2]d} python %[1]s",
+		},
+	}
+
+	dashboardScripts = []string{"streamlit_app.py", "app.py", "main.py", "dashboard.py", "Home.py"}
+)
+
+// importPattern matches a top-level import of the given package.
+func importPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(?m)^(?:import|from)\s+%s\b`, regexp.QuoteMeta(name)))
+}
+
+// FindDashboard looks for a script that imports one of the dashboard
+// frameworks. The streamlit_app.py, app.py, main.py, dashboard.py and Home.py
+// files are checked in order, first at the top level of the given directory
+// and then in its package directories. The boolean result is false when no
+// dashboard can be found.
+func FindDashboard(workingDir string) (Dashboard, bool, error) {
+	candidates, err := candidateFiles(workingDir, dashboardScripts)
+	if err != nil {
+		return Dashboard{}, false, err
+	}
+
+	for _, path := range candidates {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Dashboard{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		for _, framework := range dashboardFrameworks {
+			if framework.imports.Match(content) {
+				return Dashboard{
+					Framework: framework,
+					Script:    relativePath(workingDir, path),
+				}, true, nil
+			}
+		}
+	}
+
+	return Dashboard{}, false, nil
+}
@@ -0,0 +1,138 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDashboard(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("FindDashboard", func() {
+		context("when a top-level script imports a dashboard framework", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "streamlit_app.py"), []byte("import streamlit as st\n\nst.title('Hello')\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the dashboard", func() {
+				dashboard, found, err := pythonstart.FindDashboard(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(dashboard.Framework.Name).To(Equal("Streamlit"))
+				Expect(dashboard.Framework.Package).To(Equal("streamlit"))
+				Expect(dashboard.Script).To(Equal("streamlit_app.py"))
+			})
+		})
+
+		context("when the framework is imported with from", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte("from dash import Dash, html\n\napp = Dash(__name__)\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the dashboard", func() {
+				dashboard, found, err := pythonstart.FindDashboard(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(dashboard.Framework.Name).To(Equal("Dash"))
+				Expect(dashboard.Script).To(Equal("app.py"))
+			})
+		})
+
+		context("when a package has a dashboard script", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "module"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "module", "main.py"), []byte("import gradio as gr\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the dashboard", func() {
+				dashboard, found, err := pythonstart.FindDashboard(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(dashboard.Framework.Name).To(Equal("Gradio"))
+				Expect(dashboard.Script).To(Equal("module/main.py"))
+			})
+		})
+
+		context("when a script imports several frameworks", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "dashboard.py"), []byte("import dash\nimport panel as pn\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("prefers the framework listed first", func() {
+				dashboard, found, err := pythonstart.FindDashboard(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(dashboard.Framework.Name).To(Equal("Panel"))
+			})
+		})
+
+		context("when a script only imports a package with a similar name", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte("import dashboard_utils\nimport panels\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not find a dashboard", func() {
+				_, found, err := pythonstart.FindDashboard(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("when there is no dashboard script", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "report.py"), []byte("import streamlit\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not find a dashboard", func() {
+				_, found, err := pythonstart.FindDashboard(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
+	context("Process", func() {
+		it("serves each framework headlessly on $PORT", func() {
+			commands := map[string]string{
+				"import streamlit\n": "streamlit run app.py --server.address=0.0.0.0 --server.port=${PORT:-8080} --server.headless=true",
+				"import gradio\n":    "GRADIO_SERVER_NAME=0.0.0.0 GRADIO_SERVER_PORT=${PORT:-8080} python app.py",
+				"import panel\n":     "panel serve app.py --address=0.0.0.0 --port=${PORT:-8080} --allow-websocket-origin='*'",
+				"import dash\n":      "HOST=0.0.0.0 PORT=${PORT:-8080} python app.py",
+			}
+
+			for source, command := range commands {
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte(source), os.ModePerm)).To(Succeed())
+
+				dashboard, found, err := pythonstart.FindDashboard(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(dashboard.Process(8080)).To(Equal(packit.Process{
+					Type:    "web",
+					Command: command,
+					Default: true,
+				}))
+			}
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package pythonstart
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// DebugpyPlanEntry is the build plan entry that makes the debugpy package
+// available at launch.
+const DebugpyPlanEntry = "debugpy"
+
+// debugModules are the commands of the inferred servers that debugpy can run
+// as a module.
+var debugModules = map[string]bool{
+	"gunicorn":  true,
+	"uvicorn":   true,
+	"hypercorn": true,
+	"daphne":    true,
+}
+
+var shellSafePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// DebugProcess returns a process of type debug that runs the given process
+// under debugpy, listening for a debugger on $DEBUG_PORT or on the configured
+// port when $DEBUG_PORT is not set. Only processes that run a Python script or
+// module, or one of the inferred servers, can be debugged; the boolean result
+// is false for any other process.
+func DebugProcess(process packit.Process, config Configuration) (packit.Process, bool) {
+	command := process.Command
+	if process.Direct {
+		words := []string{shellQuote(process.Command)}
+		for _, arg := range process.Args {
+			words = append(words, shellQuote(arg))
+		}
+		command = strings.Join(words, " ")
+	}
+
+	program, target, _ := strings.Cut(strings.TrimSpace(command), " ")
+	switch {
+	case (program == "python" || program == "python3") && target != "":
+	case debugModules[program]:
+		target = strings.TrimSpace("-m " + program + " " + target)
+	default:
+		return packit.Process{}, false
+	}
+
+	listen := fmt.Sprintf("--listen 0.0.0.0:${DEBUG_PORT:-%d}", config.DebugPort)
+	if config.DebugWaitForClient {
+		listen += " --wait-for-client"
+	}
+
+	return packit.Process{
+		Type:             "debug",
+		Command:          fmt.Sprintf("python -m debugpy %s %s", listen, target),
+		WorkingDirectory: process.WorkingDirectory,
+	}, true
+}
+
+// shellQuote returns the given word quoted for a shell when it contains
+// characters the shell would interpret.
+func shellQuote(word string) string {
+	if shellSafePattern.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
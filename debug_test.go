@@ -0,0 +1,90 @@
+package pythonstart_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDebug(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		config pythonstart.Configuration
+	)
+
+	it.Before(func() {
+		config = pythonstart.Configuration{DebugPort: 5678}
+	})
+
+	context("DebugProcess", func() {
+		it("runs a Python script under debugpy", func() {
+			process, ok := pythonstart.DebugProcess(packit.Process{
+				Type:             "web",
+				Command:          "python",
+				Args:             []string{"app.py", "--name", "it's me"},
+				Direct:           true,
+				Default:          true,
+				WorkingDirectory: "/workspace/api",
+			}, config)
+			Expect(ok).To(BeTrue())
+			Expect(process).To(Equal(packit.Process{
+				Type:             "debug",
+				Command:          `python -m debugpy --listen 0.0.0.0:${DEBUG_PORT:-5678} app.py --name 'it'\''s me'`,
+				WorkingDirectory: "/workspace/api",
+			}))
+		})
+
+		it("runs an inferred server as a module under debugpy", func() {
+			process, ok := pythonstart.DebugProcess(packit.Process{
+				Type:    "web",
+				Command: "gunicorn module.wsgi:app --bind 0.0.0.0:${PORT:-8080}",
+			}, config)
+			Expect(ok).To(BeTrue())
+			Expect(process.Command).To(Equal("python -m debugpy --listen 0.0.0.0:${DEBUG_PORT:-5678} -m gunicorn module.wsgi:app --bind 0.0.0.0:${PORT:-8080}"))
+		})
+
+		context("when the debugger should be waited for", func() {
+			it.Before(func() {
+				config.DebugWaitForClient = true
+			})
+
+			it("holds the process until a client attaches", func() {
+				process, ok := pythonstart.DebugProcess(packit.Process{
+					Type:    "web",
+					Command: "python",
+					Args:    []string{"-m", "app"},
+					Direct:  true,
+				}, config)
+				Expect(ok).To(BeTrue())
+				Expect(process.Command).To(Equal("python -m debugpy --listen 0.0.0.0:${DEBUG_PORT:-5678} --wait-for-client -m app"))
+			})
+		})
+
+		context("when the process does not run Python", func() {
+			it("returns false", func() {
+				_, ok := pythonstart.DebugProcess(packit.Process{
+					Type:    "web",
+					Command: "./start.sh",
+					Direct:  true,
+				}, config)
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		context("when the process starts the Python REPL", func() {
+			it("returns false", func() {
+				_, ok := pythonstart.DebugProcess(packit.Process{
+					Type:    "web",
+					Command: "python",
+					Direct:  true,
+				}, config)
+				Expect(ok).To(BeFalse())
+			})
+		})
+	})
+}
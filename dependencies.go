@@ -0,0 +1,197 @@
+package pythonstart
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Dependencies is the set of package names declared by the application,
+// normalized as described in PEP 503.
+type Dependencies map[string]bool
+
+var (
+	requirementNamePattern = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	nameSeparatorPattern   = regexp.MustCompile(`[-_.]+`)
+)
+
+// LoadDependencies collects the names of the packages declared in the
+// requirements.txt, Pipfile.lock, poetry.lock, uv.lock, pdm.lock,
+// environment.yml and pyproject.toml files found in the given directory.
+// A file that cannot be parsed is reported as a MalformedFileError.
+func LoadDependencies(workingDir string) (Dependencies, error) {
+	dependencies := Dependencies{}
+
+	loaders := []struct {
+		file string
+		load func(string, Dependencies) error
+	}{
+		{"requirements.txt", loadRequirements},
+		{"Pipfile.lock", loadPipfileLock},
+		{"poetry.lock", loadLockPackages},
+		{"uv.lock", loadLockPackages},
+		{"pdm.lock", loadLockPackages},
+		{"environment.yml", loadCondaEnvironment},
+		{"pyproject.toml", loadPyprojectDependencies},
+	}
+
+	for _, loader := range loaders {
+		path := filepath.Join(workingDir, loader.file)
+		err := loader.load(path, dependencies)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read dependencies from %s: %w", loader.file, malformedFileError(loader.file, err))
+		}
+	}
+
+	return dependencies, nil
+}
+
+// Has reports whether the named package is declared.
+func (d Dependencies) Has(name string) bool {
+	return d[normalizePackageName(name)]
+}
+
+func (d Dependencies) add(requirement string) {
+	matches := requirementNamePattern.FindStringSubmatch(requirement)
+	if matches != nil {
+		d[normalizePackageName(matches[1])] = true
+	}
+}
+
+func normalizePackageName(name string) string {
+	return strings.ToLower(nameSeparatorPattern.ReplaceAllString(name, "-"))
+}
+
+func loadRequirements(path string, dependencies Dependencies) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.HasPrefix(strings.TrimSpace(line), "-") {
+			continue
+		}
+		dependencies.add(line)
+	}
+
+	return scanner.Err()
+}
+
+func loadPipfileLock(path string, dependencies Dependencies) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var lock struct {
+		Default map[string]json.RawMessage `json:"default"`
+	}
+	err = json.NewDecoder(file).Decode(&lock)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	for name := range lock.Default {
+		dependencies.add(name)
+	}
+
+	return nil
+}
+
+func loadLockPackages(path string, dependencies Dependencies) error {
+	var lock struct {
+		Package []struct {
+			Name string `toml:"name"`
+		} `toml:"package"`
+	}
+	_, err := toml.DecodeFile(path, &lock)
+	if err != nil {
+		return err
+	}
+
+	for _, pkg := range lock.Package {
+		dependencies.add(pkg.Name)
+	}
+
+	return nil
+}
+
+// loadCondaEnvironment reads the packages listed under the dependencies key
+// of a conda environment file, including those installed with pip.
+func loadCondaEnvironment(path string, dependencies Dependencies) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	inDependencies := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Top-level keys start at the beginning of the line, while the
+		// entries of a list may or may not be indented.
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+			inDependencies = strings.HasPrefix(line, "dependencies:")
+			continue
+		}
+
+		entry := strings.TrimSpace(line)
+		if !inDependencies || !strings.HasPrefix(entry, "-") {
+			continue
+		}
+
+		// Conda packages may name the channel they come from.
+		entry = strings.TrimSpace(strings.TrimPrefix(entry, "-"))
+		if i := strings.LastIndex(entry, "::"); i >= 0 {
+			entry = entry[i+2:]
+		}
+		dependencies.add(entry)
+	}
+
+	return scanner.Err()
+}
+
+func loadPyprojectDependencies(path string, dependencies Dependencies) error {
+	var pyproject struct {
+		Project struct {
+			Dependencies []string `toml:"dependencies"`
+		} `toml:"project"`
+		Tool struct {
+			Poetry struct {
+				Dependencies map[string]toml.Primitive `toml:"dependencies"`
+			} `toml:"poetry"`
+		} `toml:"tool"`
+	}
+	_, err := toml.DecodeFile(path, &pyproject)
+	if err != nil {
+		return err
+	}
+
+	for _, requirement := range pyproject.Project.Dependencies {
+		dependencies.add(requirement)
+	}
+
+	for name := range pyproject.Tool.Poetry.Dependencies {
+		dependencies.add(name)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencies(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("LoadDependencies", func() {
+		context("when there are no dependency files", func() {
+			it("returns no dependencies", func() {
+				dependencies, err := pythonstart.LoadDependencies(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependencies).To(BeEmpty())
+			})
+		})
+
+		context("when there is a requirements.txt", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte(`# web
+-r base.txt
+--index-url https://example.com/simple
+Flask==3.0.0
+gunicorn[gevent]>=20 ; python_version > "3.8"
+Zope.Interface
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the declared packages", func() {
+				dependencies, err := pythonstart.LoadDependencies(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependencies).To(Equal(pythonstart.Dependencies{
+					"flask":          true,
+					"gunicorn":       true,
+					"zope-interface": true,
+				}))
+				Expect(dependencies.Has("zope_interface")).To(BeTrue())
+				Expect(dependencies.Has("Flask")).To(BeTrue())
+			})
+		})
+
+		context("when there is a Pipfile.lock", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "Pipfile.lock"), []byte(`{
+	"default": {"gunicorn": {"version": "==23.0.0"}},
+	"develop": {"coverage": {"version": "==7.0.0"}}
+}`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the default packages", func() {
+				dependencies, err := pythonstart.LoadDependencies(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependencies).To(Equal(pythonstart.Dependencies{"gunicorn": true}))
+			})
+		})
+
+		context("when there is an environment.yml", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "environment.yml"), []byte(`name: analysis
+channels:
+  - conda-forge
+dependencies:
+  - python=3.12
+  - conda-forge::jupyterlab>=4 # notebooks
+  - pip:
+      - papermill==2.6.0
+variables:
+  - not-a-package
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the conda and pip packages", func() {
+				dependencies, err := pythonstart.LoadDependencies(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependencies).To(Equal(pythonstart.Dependencies{
+					"python":     true,
+					"jupyterlab": true,
+					"pip":        true,
+					"papermill":  true,
+				}))
+			})
+		})
+
+		context("when there are poetry.lock, uv.lock and pyproject.toml files", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "poetry.lock"), []byte("[[package]]\nname = \"gunicorn\"\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "uv.lock"), []byte("[[package]]\nname = \"uvicorn\"\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`[project]
+dependencies = ["fastapi>=0.100"]
+
+[tool.poetry.dependencies]
+python = "^3.10"
+Django = "^5.0"
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the packages from every file", func() {
+				dependencies, err := pythonstart.LoadDependencies(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependencies).To(Equal(pythonstart.Dependencies{
+					"gunicorn": true,
+					"uvicorn":  true,
+					"fastapi":  true,
+					"python":   true,
+					"django":   true,
+				}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the uv.lock is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "uv.lock"), []byte("%%%"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.LoadDependencies(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to read dependencies from uv.lock")))
+				})
+			})
+
+			context("when the Pipfile.lock is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "Pipfile.lock"), []byte("{"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.LoadDependencies(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to read dependencies from Pipfile.lock")))
+				})
+			})
+		})
+	})
+}
@@ -1,13 +1,13 @@
 package pythonstart
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
-	"strconv"
 
 	"github.com/paketo-buildpacks/packit/v2"
 	"github.com/paketo-buildpacks/packit/v2/fs"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
 )
 
 // BuildPlanMetadata is the buildpack specific data included in build plan
@@ -20,74 +20,154 @@ type BuildPlanMetadata struct {
 	Build  bool `toml:"build"`
 }
 
-const (
-	LiveReloadEnv            = "BP_LIVE_RELOAD_ENABLED"
-	PackageManagersEnv       = "BP_ENABLE_PACKAGE_MANAGERS"
-	PackageManagersPlanEntry = "package-managers-run"
-)
+const PackageManagersPlanEntry = "package-managers-run"
+
+// maxReportedSources is the number of Python sources listed in the detect
+// output.
+const maxReportedSources = 5
 
 // Detect will return a packit.DetectFunc that will be invoked during the
 // detect phase of the buildpack lifecycle.
 //
 // If this buildpack detects files that indicate your app is a Python project,
-// it will pass detection. It will require "cpython" OR "cpython" and
+// including *.py files up to BP_PYTHON_SOURCE_DEPTH directories deep, it will
+// pass detection. It will require "cpython" OR "cpython" and
 // "site-packages" OR "conda-environment" as launch-time build plan
 // requirements, depending on whether it detects files indicating the use of
 // different package managers.
 //
+// Only the plans of the package managers whose files are present are
+// offered, in the order uv, pixi, conda, poetry, pdm, hatch, pipenv, pip. A
+// pyproject.toml counts towards the package manager that its tool tables or
+// build backend belong to. The plan that requires only "cpython" is offered when no package
+// manager files are found. If BP_PYTHON_PERMISSIVE_PLANS=true, every package
+// manager plan is offered regardless of the files present.
+//
+// If it finds a Django project, or an ASGI or WSGI application that will be
+// served by an application server, it will not offer the plan that requires
+// only "cpython", since the framework or server must be installed alongside
+// the application dependencies.
+//
+// When BP_PYTHON_APP_ROOT is set, every check is made in that directory of
+// the workspace instead of at its root.
+//
+// Every plan alternative also provides and requires a "python-start" entry
+// whose metadata is a DetectionReport for the build to print.
+//
 // If BP_LIVE_RELOAD_ENABLED=true in the build environment, it will
 // additionally require "watchexec" at launch-time
-func Detect() packit.DetectFunc {
+func Detect(logger scribe.Emitter) packit.DetectFunc {
 	return func(context packit.DetectContext) (packit.DetectResult, error) {
-		envFile, err := fs.Exists(filepath.Join(context.WorkingDir, "environment.yml"))
+		config, err := NewConfigurationLoader().Load(context.WorkingDir)
+		if err != nil {
+			return packit.DetectResult{}, failOnMalformedFile(err)
+		}
+
+		appDir, err := config.AppDir(context.WorkingDir)
+		if err != nil {
+			return packit.DetectResult{}, err
+		}
+
+		envFile, err := fs.Exists(filepath.Join(appDir, "environment.yml"))
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat environment.yml: %w", err)
 		}
 
-		pixiEnvFile, err := fs.Exists(filepath.Join(context.WorkingDir, "pixi.lock"))
+		pixiEnvFile, err := fs.Exists(filepath.Join(appDir, "pixi.lock"))
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat pixi.lock: %w", err)
 		}
 
-		requirementsFile, err := fs.Exists(filepath.Join(context.WorkingDir, "requirements.txt"))
+		requirementsFile, err := fs.Exists(filepath.Join(appDir, "requirements.txt"))
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat requirements.txt: %w", err)
 		}
 
-		lockFile, err := fs.Exists(filepath.Join(context.WorkingDir, "package-list.txt"))
+		lockFile, err := fs.Exists(filepath.Join(appDir, "package-list.txt"))
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat package-list.txt: %w", err)
 		}
 
-		uvLockFile, err := fs.Exists(filepath.Join(context.WorkingDir, "uv.lock"))
+		uvLockFile, err := fs.Exists(filepath.Join(appDir, "uv.lock"))
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat uv.lock: %w", err)
 		}
 
-		pipenvLockFile, err := fs.Exists(filepath.Join(context.WorkingDir, "Pipfile.lock"))
+		pipenvLockFile, err := fs.Exists(filepath.Join(appDir, "Pipfile.lock"))
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat Pipfile.lock: %w", err)
 		}
 
-		pyprojectTOMLFile, err := fs.Exists(filepath.Join(context.WorkingDir, "pyproject.toml"))
+		poetryLockFile, err := fs.Exists(filepath.Join(appDir, "poetry.lock"))
+		if err != nil {
+			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat poetry.lock: %w", err)
+		}
+
+		pdmLockFile, err := fs.Exists(filepath.Join(appDir, "pdm.lock"))
+		if err != nil {
+			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat pdm.lock: %w", err)
+		}
+
+		setupPy, err := fs.Exists(filepath.Join(appDir, "setup.py"))
+		if err != nil {
+			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat setup.py: %w", err)
+		}
+
+		setupCfg, err := fs.Exists(filepath.Join(appDir, "setup.cfg"))
+		if err != nil {
+			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat setup.cfg: %w", err)
+		}
+
+		pipfile, err := fs.Exists(filepath.Join(appDir, "Pipfile"))
+		if err != nil {
+			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat Pipfile: %w", err)
+		}
+
+		pyprojectTOMLFile, err := fs.Exists(filepath.Join(appDir, "pyproject.toml"))
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to stat pyproject.toml: %w", err)
 		}
 
-		pythonFiles, err := filepath.Glob(filepath.Join(context.WorkingDir, "*.py"))
+		pythonFiles, err := FindPythonSources(appDir, config.SourceDepth)
 		if err != nil {
 			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to find *.py files: %w", err)
 		}
 
+		if len(pythonFiles) > 0 {
+			logger.Process("Found %d Python source file(s) within %d directories of the application root", len(pythonFiles), config.SourceDepth)
+			for i, file := range pythonFiles {
+				if i == maxReportedSources {
+					logger.Subprocess("... and %d more", len(pythonFiles)-maxReportedSources)
+					break
+				}
+				logger.Subprocess(file)
+			}
+			logger.Break()
+		}
+
+		notebooks, err := FindNotebooks(appDir, config.SourceDepth)
+		if err != nil {
+			return packit.DetectResult{}, packit.Fail.WithMessage("failed trying to find *.ipynb files: %w", err)
+		}
+
+		if len(notebooks) > 0 {
+			logger.Process("Found %d Jupyter notebook(s) within %d directories of the application root", len(notebooks), config.SourceDepth)
+			logger.Break()
+		}
+
 		if !envFile &&
 			!pixiEnvFile &&
 			!requirementsFile &&
 			!lockFile &&
 			!uvLockFile &&
 			!pipenvLockFile &&
+			!pdmLockFile &&
+			!setupPy &&
+			!setupCfg &&
 			!pyprojectTOMLFile &&
-			len(pythonFiles) < 1 {
-			return packit.DetectResult{}, packit.Fail.WithMessage("No *.py, environment.yml, pixi.lock, requirements.txt, uv.lock, Pipfile.lock, pyproject.toml, or package-list.txt found")
+			len(pythonFiles) < 1 &&
+			len(notebooks) < 1 {
+			return packit.DetectResult{}, packit.Fail.WithMessage("No *.py, *.ipynb, environment.yml, pixi.lock, requirements.txt, uv.lock, Pipfile.lock, pdm.lock, setup.py, setup.cfg, pyproject.toml, or package-list.txt found")
 		}
 
 		simplePlan := packit.BuildPlan{
@@ -204,22 +284,185 @@ func Detect() packit.DetectFunc {
 			},
 		}
 
-		plans := []packit.BuildPlan{pipPlan, pipenvPlan, condaPlan, pixiPlan, poetryInstallPlan, simplePlan}
+		pdmPlan := packit.BuildPlan{
+			Provides: []packit.BuildPlanProvision{},
+			Requires: []packit.BuildPlanRequirement{
+				{
+					Name: "cpython",
+					Metadata: BuildPlanMetadata{
+						Launch: true,
+					},
+				},
+				{
+					Name: "pdm",
+					Metadata: BuildPlanMetadata{
+						Launch: true,
+					},
+				},
+				{
+					Name: "pdm-venv",
+					Metadata: BuildPlanMetadata{
+						Launch: true,
+					},
+				},
+			},
+		}
 
-		// The current build plan from the python buildpack will make an uv project be detected as
-		// a poetry project due to the pyproject.toml presence hence we workaround the issue by only
-		// requiring uv.
-		// Note: this is temporary.
-		if uvLockFile {
-			plans = []packit.BuildPlan{uvPlan}
+		hatchPlan := packit.BuildPlan{
+			Provides: []packit.BuildPlanProvision{},
+			Requires: []packit.BuildPlanRequirement{
+				{
+					Name: "cpython",
+					Metadata: BuildPlanMetadata{
+						Launch: true,
+					},
+				},
+				{
+					Name: "hatch",
+					Metadata: BuildPlanMetadata{
+						Launch: true,
+					},
+				},
+				{
+					Name: "hatch-venv",
+					Metadata: BuildPlanMetadata{
+						Launch: true,
+					},
+				},
+			},
+		}
+
+		report := DetectionReport{
+			PythonSources: len(pythonFiles),
+			Notebooks:     len(notebooks),
+			Settings:      config.Settings,
+		}
+		for _, file := range []struct {
+			name  string
+			found bool
+		}{
+			{"environment.yml", envFile},
+			{"pixi.lock", pixiEnvFile},
+			{"requirements.txt", requirementsFile},
+			{"package-list.txt", lockFile},
+			{"uv.lock", uvLockFile},
+			{"Pipfile.lock", pipenvLockFile},
+			{"Pipfile", pipfile},
+			{"poetry.lock", poetryLockFile},
+			{"pdm.lock", pdmLockFile},
+			{"setup.py", setupPy},
+			{"setup.cfg", setupCfg},
+			{"pyproject.toml", pyprojectTOMLFile},
+		} {
+			if file.found {
+				report.Files = append(report.Files, file.name)
+			}
 		}
 
-		shouldReload, err := checkLiveReloadEnabled()
+		// Django projects, applications served by an application server or a
+		// dashboard framework, task queue workers and notebooks need their
+		// dependencies installed, which the simple plan does not provide.
+		requiresPackagesReason, err := checkRequiresPackages(appDir, config, notebooks)
 		if err != nil {
-			return packit.DetectResult{}, err
+			return packit.DetectResult{}, failOnMalformedFile(err)
+		}
+
+		pyproject, _, err := LoadPyproject(appDir)
+		if err != nil {
+			return packit.DetectResult{}, failOnMalformedFile(err)
+		}
+
+		pyprojectManager, pyprojectEvidence := pyproject.Classify()
+		if pyprojectManager != "" {
+			report.Pyproject = fmt.Sprintf("%s (%s)", pyprojectManager, pyprojectEvidence)
+		}
+
+		usesUV := uvLockFile || pyprojectManager == UVManager
+		usesPDM := pdmLockFile || pyprojectManager == PDMManager
+
+		// Package manager plans in order of precedence, each offered only when
+		// the files it installs from are present. Hatch projects also offer the
+		// pip plan, which builds them with their PEP 517 backend.
+		candidates := []struct {
+			name    string
+			plan    packit.BuildPlan
+			found   bool
+			missing string
+		}{
+			{"uv", uvPlan, usesUV, "no uv.lock or uv project found"},
+			{"pixi", pixiPlan, pixiEnvFile, "no pixi.lock found"},
+			{"conda", condaPlan, envFile || lockFile, "no environment.yml or package-list.txt found"},
+			{"poetry", poetryInstallPlan, poetryLockFile || pyprojectManager == PoetryManager, "no poetry.lock or Poetry project found"},
+			{"pdm", pdmPlan, usesPDM, "no pdm.lock or PDM project found"},
+			{"hatch", hatchPlan, pyprojectManager == HatchManager, "no Hatch project found"},
+			{"pipenv", pipenvPlan, pipenvLockFile || pipfile, "no Pipfile.lock or Pipfile found"},
+			{"pip", pipPlan, requirementsFile || setupPy || setupCfg || pyprojectManager == PipManager || pyprojectManager == HatchManager, "no requirements.txt, setup.py, setup.cfg or pip installable project found"},
+		}
+
+		// The permissive plans are those offered before plans were narrowed to
+		// the files present, along with the uv and PDM plans when their files
+		// are present.
+		permissivePlans := []packit.BuildPlan{pipPlan, pipenvPlan, condaPlan, pixiPlan, poetryInstallPlan}
+		permissivePlanNames := []string{"pip", "pipenv", "conda", "pixi", "poetry"}
+		if usesUV {
+			permissivePlans = append([]packit.BuildPlan{uvPlan}, permissivePlans...)
+			permissivePlanNames = append([]string{"uv"}, permissivePlanNames...)
+		}
+		if usesPDM {
+			permissivePlans = append(permissivePlans, pdmPlan)
+			permissivePlanNames = append(permissivePlanNames, "pdm")
+		}
+
+		var (
+			plans     []packit.BuildPlan
+			planNames []string
+		)
+		includeSimplePlan := requiresPackagesReason == ""
+		if !includeSimplePlan {
+			report.Suppressed = append(report.Suppressed, SuppressedPlan{Plan: "simple", Reason: requiresPackagesReason})
 		}
 
-		if shouldReload {
+		if config.PermissivePlans {
+			plans = permissivePlans
+			planNames = permissivePlanNames
+		} else {
+			var suppressed []SuppressedPlan
+			for _, candidate := range candidates {
+				if !candidate.found {
+					suppressed = append(suppressed, SuppressedPlan{Plan: candidate.name, Reason: candidate.missing})
+					continue
+				}
+				plans = append(plans, candidate.plan)
+				planNames = append(planNames, candidate.name)
+			}
+
+			switch {
+			case len(plans) > 0:
+				report.Suppressed = append(report.Suppressed, suppressed...)
+				if includeSimplePlan {
+					report.Suppressed = append(report.Suppressed, SuppressedPlan{Plan: "simple", Reason: "package manager files found"})
+					includeSimplePlan = false
+				}
+
+			// An application that needs packages installed without declaring
+			// them may still resolve a plan through another buildpack, so every
+			// package manager plan is offered.
+			case !includeSimplePlan:
+				plans = permissivePlans
+				planNames = permissivePlanNames
+
+			default:
+				report.Suppressed = append(report.Suppressed, suppressed...)
+			}
+		}
+
+		if includeSimplePlan {
+			plans = append(plans, simplePlan)
+			planNames = append(planNames, "simple")
+		}
+		report.Offered = planNames
+
+		if config.LiveReloadEnabled {
 			for i := range plans {
 				plans[i].Requires = append(plans[i].Requires, packit.BuildPlanRequirement{
 					Name: "watchexec",
@@ -230,14 +473,21 @@ func Detect() packit.DetectFunc {
 			}
 		}
 
-		shouldUsePackageManagers, err := checkShouldEnablePackageManagers()
-		if err != nil {
-			return packit.DetectResult{}, err
+		if config.DebugEnabled {
+			for i := range plans {
+				plans[i].Requires = append(plans[i].Requires, packit.BuildPlanRequirement{
+					Name: DebugpyPlanEntry,
+					Metadata: BuildPlanMetadata{
+						Launch: true,
+					},
+				})
+			}
 		}
-		if shouldUsePackageManagers {
+
+		if config.PackageManagersEnabled {
 			for i := range plans {
 				// Simple plan does not use package-managers
-				if len(plans) > 1 && i == len(plans) - 1 {
+				if planNames[i] == "simple" {
 					continue
 				}
 				plans[i].Requires = append(plans[i].Requires, packit.BuildPlanRequirement{
@@ -249,34 +499,97 @@ func Detect() packit.DetectFunc {
 			}
 		}
 
+		// Each alternative hands a report naming itself to the build, which
+		// receives the report of the alternative that was resolved.
+		for i := range plans {
+			planReport := report
+			planReport.Plan = planNames[i]
+
+			plans[i].Provides = append(plans[i].Provides, packit.BuildPlanProvision{
+				Name: DetectionReportPlanEntry,
+			})
+			plans[i].Requires = append(plans[i].Requires, packit.BuildPlanRequirement{
+				Name:     DetectionReportPlanEntry,
+				Metadata: planReport,
+			})
+		}
+
 		return packit.DetectResult{
 			Plan: or(plans...),
 		}, nil
 	}
 }
 
-func checkLiveReloadEnabled() (bool, error) {
-	if reload, ok := os.LookupEnv(LiveReloadEnv); ok {
-		shouldEnableReload, err := strconv.ParseBool(reload)
-		if err != nil {
-			return false, fmt.Errorf("failed to parse BP_LIVE_RELOAD_ENABLED value %s: %w", reload, err)
-		}
-		return shouldEnableReload, nil
+// checkRequiresPackages returns why the application needs its dependencies
+// installed, or an empty string when it does not.
+func checkRequiresPackages(workingDir string, config Configuration, notebooks []string) (string, error) {
+	_, isDjango, err := FindDjangoProject(workingDir)
+	if err != nil {
+		return "", err
+	}
+
+	if isDjango {
+		return "Django project found", nil
+	}
+
+	dependencies, err := LoadDependencies(workingDir)
+	if err != nil {
+		return "", err
+	}
+
+	asgiApp, found, err := FindASGIApp(workingDir, config.ASGIApp)
+	if err != nil {
+		return "", err
+	}
+
+	if server, ok := SelectASGIServer(dependencies); found && ok {
+		return fmt.Sprintf("ASGI application %s will be served by %s", asgiApp, server.Name), nil
+	}
+
+	wsgiApp, found, err := FindWSGIApp(workingDir, config.WSGIApp)
+	if err != nil {
+		return "", err
+	}
+
+	if found && dependencies.Has("gunicorn") {
+		return fmt.Sprintf("WSGI application %s will be served by gunicorn", wsgiApp), nil
+	}
+
+	dashboard, found, err := FindDashboard(workingDir)
+	if err != nil {
+		return "", err
+	}
+
+	if found && dependencies.Has(dashboard.Framework.Package) {
+		return fmt.Sprintf("%s application %s will be served by %s", dashboard.Framework.Name, dashboard.Script, dashboard.Framework.Package), nil
+	}
+
+	worker, found, err := FindWorkerApp(workingDir, dependencies)
+	if err != nil {
+		return "", err
 	}
-	return false, nil
-}
 
-func checkShouldEnablePackageManagers() (bool, error) {
-	shouldUsePackageManagers := false
+	if found {
+		return fmt.Sprintf("%s application %s will be run by a worker", worker.Queue.Name, worker.Reference()), nil
+	}
+
+	if config.Notebook != "" {
+		return fmt.Sprintf("notebook %s will be executed by the notebook process", config.Notebook), nil
+	}
 
-	if usePackageManagers, ok := os.LookupEnv(PackageManagersEnv); ok {
-		shouldUsePackageManagers, err := strconv.ParseBool(usePackageManagers)
+	// Notebooks are only served when there is no entrypoint to run instead.
+	if len(notebooks) > 0 {
+		entrypoint, err := InferEntrypoint(workingDir)
 		if err != nil {
-			return false, fmt.Errorf("failed to parse %s value %s: %w", PackageManagersEnv, usePackageManagers, err)
+			return "", err
+		}
+
+		if len(entrypoint.Args) == 0 {
+			return "Jupyter notebooks found", nil
 		}
-		return shouldUsePackageManagers, nil
 	}
-	return shouldUsePackageManagers, nil
+
+	return "", nil
 }
 
 func or(plans ...packit.BuildPlan) packit.BuildPlan {
@@ -293,3 +606,14 @@ func or(plans ...packit.BuildPlan) packit.BuildPlan {
 	}
 	return combinedPlan
 }
+
+// failOnMalformedFile turns the error of an application file that cannot be
+// parsed into a detection failure, so that the buildpack is not selected for
+// a build that would fail on the same file.
+func failOnMalformedFile(err error) error {
+	var malformed MalformedFileError
+	if errors.As(err, &malformed) {
+		return packit.Fail.WithMessage("%s", malformed)
+	}
+	return err
+}
@@ -1,11 +1,13 @@
 package pythonstart_test
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
 	pythonstart "github.com/paketo-buildpacks/python-start"
 	"github.com/sclevine/spec"
 
@@ -17,6 +19,7 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 		Expect = NewWithT(t).Expect
 
 		workingDir string
+		buffer     *bytes.Buffer
 		detect     packit.DetectFunc
 	)
 
@@ -27,7 +30,8 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 
 		Expect(os.WriteFile(filepath.Join(workingDir, "x.py"), []byte{}, os.ModePerm)).To(Succeed())
 
-		detect = pythonstart.Detect()
+		buffer = bytes.NewBuffer(nil)
+		detect = pythonstart.Detect(scribe.NewEmitter(buffer))
 	})
 
 	it.After(func() {
@@ -35,13 +39,37 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 	})
 
 	context("detection phase", func() {
-		context("without uv.lock file", func() {
-			it("detects", func() {
+		context("without package manager files", func() {
+			it("offers only the plan that requires cpython", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
+					Provides: []packit.BuildPlanProvision{},
+					Requires: []packit.BuildPlanRequirement{
+						{
+							Name: "cpython",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+					},
+				}))
+			})
+		})
+
+		context("when BP_PYTHON_PERMISSIVE_PLANS=true in the build environment", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.PermissivePlansEnv, "true")
+			})
+
+			it("offers every plan", func() {
 				result, err := detect(packit.DetectContext{
 					WorkingDir: workingDir,
 				})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
 					Provides: []packit.BuildPlanProvision{},
 					Requires: []packit.BuildPlanRequirement{
 						{
@@ -152,7 +180,7 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 					WorkingDir: workingDir,
 				})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(result.Plan).To(Equal(packit.BuildPlan{
+				Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
 					Provides: []packit.BuildPlanProvision{},
 					Requires: []packit.BuildPlanRequirement{
 						{
@@ -172,12 +200,16 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 			})
 
 			context("without uv.lock", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.PermissivePlansEnv, "true")
+				})
+
 				it("requires watchexec at launch", func() {
 					result, err := detect(packit.DetectContext{
 						WorkingDir: workingDir,
 					})
 					Expect(err).NotTo(HaveOccurred())
-					Expect(result.Plan).To(Equal(packit.BuildPlan{
+					Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
 						Provides: []packit.BuildPlanProvision{},
 						Requires: []packit.BuildPlanRequirement{
 							{
@@ -325,7 +357,7 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 						WorkingDir: workingDir,
 					})
 					Expect(err).NotTo(HaveOccurred())
-					Expect(result.Plan).To(Equal(packit.BuildPlan{
+					Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
 						Provides: []packit.BuildPlanProvision{},
 						Requires: []packit.BuildPlanRequirement{
 							{
@@ -346,18 +378,59 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when BP_PYTHON_DEBUG_ENABLED=true in the build environment", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "Pipfile"), []byte{}, os.ModePerm)).To(Succeed())
+				t.Setenv(pythonstart.LiveReloadEnv, "true")
+				t.Setenv(pythonstart.DebugEnv, "true")
+			})
+
+			it("requires debugpy at launch in every plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				plan := withoutDetectionReport(result.Plan)
+				var names [][]string
+				for _, alternative := range append([]packit.BuildPlan{plan}, plan.Or...) {
+					Expect(alternative.Requires).To(ContainElement(packit.BuildPlanRequirement{
+						Name: pythonstart.DebugpyPlanEntry,
+						Metadata: pythonstart.BuildPlanMetadata{
+							Launch: true,
+						},
+					}))
+
+					var requires []string
+					for _, requirement := range alternative.Requires {
+						requires = append(requires, requirement.Name)
+					}
+					names = append(names, requires)
+				}
+				Expect(names).To(Equal([][]string{
+					{"cpython", "site-packages", "pipenv", "watchexec", "debugpy"},
+					{"cpython", "site-packages", "watchexec", "debugpy"},
+				}))
+			})
+		})
+
 		context("when BP_ENABLE_PACKAGE_MANAGERS=true in the build environment", func() {
 			it.Before(func() {
 				t.Setenv(pythonstart.PackageManagersEnv, "true")
 			})
 
 			context("without uv.lock", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.PermissivePlansEnv, "true")
+				})
+
 				it("requires watchexec at launch", func() {
 					result, err := detect(packit.DetectContext{
 						WorkingDir: workingDir,
 					})
 					Expect(err).NotTo(HaveOccurred())
-					Expect(result.Plan).To(Equal(packit.BuildPlan{
+					Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
 						Provides: []packit.BuildPlanProvision{},
 						Requires: []packit.BuildPlanRequirement{
 							{
@@ -499,7 +572,7 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 						WorkingDir: workingDir,
 					})
 					Expect(err).NotTo(HaveOccurred())
-					Expect(result.Plan).To(Equal(packit.BuildPlan{
+					Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
 						Provides: []packit.BuildPlanProvision{},
 						Requires: []packit.BuildPlanRequirement{
 							{
@@ -520,6 +593,415 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when a WSGI application will be served by gunicorn", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("application = make_app()\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("gunicorn\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not offer the plan without site-packages", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "site-packages",
+					Metadata: pythonstart.BuildPlanMetadata{
+						Launch: true,
+					},
+				}))
+				Expect(result.Plan.Or).To(BeEmpty())
+			})
+		})
+
+		context("when a dashboard will be served by its framework", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte("import gradio as gr\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("gradio\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not offer the plan without site-packages", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "site-packages",
+					Metadata: pythonstart.BuildPlanMetadata{
+						Launch: true,
+					},
+				}))
+				Expect(result.Plan.Or).To(BeEmpty())
+			})
+		})
+
+		context("when a lock file cannot be parsed", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("application = make_app()\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("gunicorn\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "poetry.lock"), []byte("[[[\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("fails detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(BeAssignableToTypeOf(packit.Fail))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse poetry.lock")))
+			})
+		})
+
+		context("when pyproject.toml cannot be parsed", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[[[\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("fails detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(BeAssignableToTypeOf(packit.Fail))
+				Expect(err).To(MatchError(ContainSubstring("failed to parse pyproject.toml")))
+			})
+		})
+
+		context("when a task queue application will be run by a worker", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("import dramatiq\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("dramatiq\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not offer the plan without site-packages", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "site-packages",
+					Metadata: pythonstart.BuildPlanMetadata{
+						Launch: true,
+					},
+				}))
+				Expect(result.Plan.Or).To(BeEmpty())
+			})
+		})
+
+		context("when several package managers are present", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "Pipfile.lock"), []byte("{}"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "environment.yml"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "pixi.lock"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[tool.poetry]\nname = \"app\"\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("offers their plans in order of precedence", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				plan := withoutDetectionReport(result.Plan)
+				var names [][]string
+				for _, alternative := range append([]packit.BuildPlan{plan}, plan.Or...) {
+					var requires []string
+					for _, requirement := range alternative.Requires {
+						requires = append(requires, requirement.Name)
+					}
+					names = append(names, requires)
+				}
+				Expect(names).To(Equal([][]string{
+					{"pixi-environment"},
+					{"conda-environment"},
+					{"cpython", "poetry", "poetry-venv"},
+					{"cpython", "site-packages", "pipenv"},
+					{"cpython", "site-packages"},
+				}))
+			})
+		})
+
+		context("when there is a pdm.lock file", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pdm.lock"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("offers only the PDM plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
+					Provides: []packit.BuildPlanProvision{},
+					Requires: []packit.BuildPlanRequirement{
+						{
+							Name: "cpython",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+						{
+							Name: "pdm",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+						{
+							Name: "pdm-venv",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+					},
+				}))
+			})
+
+			context("alongside other lock files", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "poetry.lock"), []byte{}, os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(workingDir, "Pipfile.lock"), []byte("{}"), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(workingDir, "uv.lock"), []byte{}, os.ModePerm)).To(Succeed())
+				})
+
+				it("offers the PDM plan after the uv and poetry plans", func() {
+					result, err := detect(packit.DetectContext{
+						WorkingDir: workingDir,
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					report := result.Plan.Requires[len(result.Plan.Requires)-1].Metadata.(pythonstart.DetectionReport)
+					Expect(report.Offered).To(Equal([]string{"uv", "poetry", "pdm", "pipenv"}))
+					Expect(result.Plan.Or[1].Requires).To(ContainElement(packit.BuildPlanRequirement{
+						Name: "pdm-venv",
+						Metadata: pythonstart.BuildPlanMetadata{
+							Launch: true,
+						},
+					}))
+				})
+			})
+		})
+
+		context("when pyproject.toml is a Hatch project", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[build-system]
+requires = ["hatchling"]
+build-backend = "hatchling.build"
+
+[project]
+name = "app"
+
+[tool.hatch.envs.default.scripts]
+serve = "uvicorn app:app"
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("offers the Hatch plan before the pip plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				plan := withoutDetectionReport(result.Plan)
+				Expect(plan.Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: "cpython",
+						Metadata: pythonstart.BuildPlanMetadata{
+							Launch: true,
+						},
+					},
+					{
+						Name: "hatch",
+						Metadata: pythonstart.BuildPlanMetadata{
+							Launch: true,
+						},
+					},
+					{
+						Name: "hatch-venv",
+						Metadata: pythonstart.BuildPlanMetadata{
+							Launch: true,
+						},
+					},
+				}))
+				Expect(plan.Or).To(HaveLen(1))
+				Expect(plan.Or[0].Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "site-packages",
+					Metadata: pythonstart.BuildPlanMetadata{
+						Launch: true,
+					},
+				}))
+			})
+		})
+
+		context("when pyproject.toml declares a PEP 621 project", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[project]\nname = \"app\"\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("offers only the pip plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
+					Provides: []packit.BuildPlanProvision{},
+					Requires: []packit.BuildPlanRequirement{
+						{
+							Name: "cpython",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+						{
+							Name: "site-packages",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+					},
+				}))
+			})
+		})
+
+		context("when pyproject.toml belongs to a package manager", func() {
+			for _, example := range []struct {
+				content string
+				plan    string
+			}{
+				{"[build-system]\nbuild-backend = \"poetry.core.masonry.api\"\n[project]\nname = \"app\"\n", "poetry-venv"},
+				{"[project]\nname = \"app\"\n[tool.uv]\ndev-dependencies = []\n", "uv-environment"},
+				{"[build-system]\nbuild-backend = \"uv_build\"\n", "uv-environment"},
+				{"[build-system]\nbuild-backend = \"pdm.backend\"\n", "pdm-venv"},
+				{"[build-system]\nbuild-backend = \"setuptools.build_meta:__legacy__\"\n", "site-packages"},
+			} {
+				example := example
+
+				context(example.plan+" for "+example.content, func() {
+					it.Before(func() {
+						Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(example.content), os.ModePerm)).To(Succeed())
+					})
+
+					it("offers only the matching plan", func() {
+						result, err := detect(packit.DetectContext{
+							WorkingDir: workingDir,
+						})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result.Plan.Or).To(BeEmpty())
+
+						var names []string
+						for _, requirement := range result.Plan.Requires {
+							names = append(names, requirement.Name)
+						}
+						Expect(names).To(ContainElement(example.plan))
+					})
+				})
+			}
+		})
+
+		context("when pyproject.toml only configures tools", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[tool.black]\nline-length = 100\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("offers only the plan that requires cpython", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Or).To(BeEmpty())
+				Expect(withoutDetectionReport(result.Plan).Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: "cpython",
+						Metadata: pythonstart.BuildPlanMetadata{
+							Launch: true,
+						},
+					},
+				}))
+			})
+		})
+
+		context("when the app is a Django project", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "manage.py"), []byte(`os.environ.setdefault("DJANGO_SETTINGS_MODULE", "mysite.settings")`), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not offer the plan without site-packages", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Or).To(HaveLen(4))
+			})
+		})
+
+		context("detection report", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("gunicorn\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("app = Flask(__name__)\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("hands every alternative a report naming it", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				report := pythonstart.DetectionReport{
+					Files:         []string{"requirements.txt"},
+					PythonSources: 2,
+					Offered:       []string{"pip"},
+					Suppressed: []pythonstart.SuppressedPlan{
+						{Plan: "simple", Reason: "WSGI application wsgi:app will be served by gunicorn"},
+						{Plan: "uv", Reason: "no uv.lock or uv project found"},
+						{Plan: "pixi", Reason: "no pixi.lock found"},
+						{Plan: "conda", Reason: "no environment.yml or package-list.txt found"},
+						{Plan: "poetry", Reason: "no poetry.lock or Poetry project found"},
+						{Plan: "pdm", Reason: "no pdm.lock or PDM project found"},
+						{Plan: "hatch", Reason: "no Hatch project found"},
+						{Plan: "pipenv", Reason: "no Pipfile.lock or Pipfile found"},
+					},
+				}
+
+				plans := append([]packit.BuildPlan{result.Plan}, result.Plan.Or...)
+				Expect(plans).To(HaveLen(1))
+				for i, plan := range plans {
+					Expect(plan.Provides).To(ContainElement(packit.BuildPlanProvision{Name: pythonstart.DetectionReportPlanEntry}))
+
+					requirement := plan.Requires[len(plan.Requires)-1]
+					Expect(requirement.Name).To(Equal(pythonstart.DetectionReportPlanEntry))
+
+					planReport, ok := requirement.Metadata.(pythonstart.DetectionReport)
+					Expect(ok).To(BeTrue())
+					Expect(planReport.Settings).To(HaveLen(len(pythonstart.ConfigurationOptions)))
+
+					planReport.Settings = nil
+					report.Plan = report.Offered[i]
+					Expect(planReport).To(Equal(report))
+				}
+			})
+
+			context("when there is a uv.lock file", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte{}, os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(workingDir, "uv.lock"), []byte{}, os.ModePerm)).To(Succeed())
+				})
+
+				it("offers the uv plan first", func() {
+					result, err := detect(packit.DetectContext{
+						WorkingDir: workingDir,
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					report := result.Plan.Requires[len(result.Plan.Requires)-1].Metadata.(pythonstart.DetectionReport)
+					Expect(report.Plan).To(Equal("uv"))
+					Expect(report.Offered).To(Equal([]string{"uv", "pip"}))
+					Expect(report.Suppressed).To(ContainElement(
+						pythonstart.SuppressedPlan{Plan: "pixi", Reason: "no pixi.lock found"},
+					))
+				})
+			})
+		})
+
 		context("When only an environment.yml file is present", func() {
 			it.Before(func() {
 				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
@@ -534,6 +1016,88 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("When only notebooks and an environment.yml file are present", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "analysis.ipynb"), []byte("{}"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "environment.yml"), []byte("dependencies:\n  - jupyterlab\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("offers the conda plan and reports the notebooks", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				report := result.Plan.Requires[len(result.Plan.Requires)-1].Metadata.(pythonstart.DetectionReport)
+				Expect(report.Offered).To(Equal([]string{"conda"}))
+				Expect(report.Notebooks).To(Equal(1))
+				Expect(report.Suppressed).To(ContainElement(pythonstart.SuppressedPlan{Plan: "simple", Reason: "Jupyter notebooks found"}))
+				Expect(buffer.String()).To(ContainSubstring("Found 1 Jupyter notebook(s) within 3 directories of the application root"))
+			})
+		})
+
+		context("When only notebooks are present", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "analysis.ipynb"), []byte("{}"), os.ModePerm)).To(Succeed())
+			})
+
+			it("passes detection without the simple plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				report := result.Plan.Requires[len(result.Plan.Requires)-1].Metadata.(pythonstart.DetectionReport)
+				Expect(report.Offered).NotTo(ContainElement("simple"))
+			})
+		})
+
+		context("When notebooks are present alongside an entrypoint", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "server.py"), []byte("import http.server\n"), os.ModePerm)).To(Succeed())
+				Expect(os.Mkdir(filepath.Join(workingDir, "notebooks"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "notebooks", "explore.ipynb"), []byte("{}"), os.ModePerm)).To(Succeed())
+			})
+
+			it("keeps the simple plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				report := result.Plan.Requires[len(result.Plan.Requires)-1].Metadata.(pythonstart.DetectionReport)
+				Expect(report.Notebooks).To(Equal(1))
+				Expect(report.Suppressed).NotTo(ContainElement(HaveField("Plan", "simple")))
+				Expect(withoutDetectionReport(result.Plan).Requires).To(Equal([]packit.BuildPlanRequirement{
+					{
+						Name: "cpython",
+						Metadata: pythonstart.BuildPlanMetadata{
+							Launch: true,
+						},
+					},
+				}))
+			})
+
+			context("when BP_PYTHON_NOTEBOOK is set", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.NotebookEnv, "notebooks/explore.ipynb")
+				})
+
+				it("does not offer the simple plan", func() {
+					result, err := detect(packit.DetectContext{
+						WorkingDir: workingDir,
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					report := result.Plan.Requires[len(result.Plan.Requires)-1].Metadata.(pythonstart.DetectionReport)
+					Expect(report.Suppressed).To(ContainElement(pythonstart.SuppressedPlan{Plan: "simple", Reason: "notebook notebooks/explore.ipynb will be executed by the notebook process"}))
+				})
+			})
+		})
+
 		context("When only a requirements.txt file is present", func() {
 			it.Before(func() {
 				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
@@ -576,6 +1140,73 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("When only a pdm.lock file is present", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "pdm.lock"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("passes detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		context("When only a setup.cfg file and a package directory are present", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "setup.cfg"), []byte("[metadata]\nname = app\n"), os.ModePerm)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "app"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "app", "data.json"), []byte("{}"), os.ModePerm)).To(Succeed())
+			})
+
+			it("passes detection and offers only the pip plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(withoutDetectionReport(result.Plan)).To(Equal(packit.BuildPlan{
+					Provides: []packit.BuildPlanProvision{},
+					Requires: []packit.BuildPlanRequirement{
+						{
+							Name: "cpython",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+						{
+							Name: "site-packages",
+							Metadata: pythonstart.BuildPlanMetadata{
+								Launch: true,
+							},
+						},
+					},
+				}))
+			})
+		})
+
+		context("When a setup.py file is present", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "setup.py"), []byte("from setuptools import setup\nsetup(name='app')\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("offers only the pip plan", func() {
+				result, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Plan.Or).To(BeEmpty())
+				Expect(result.Plan.Requires).To(ContainElement(packit.BuildPlanRequirement{
+					Name: "site-packages",
+					Metadata: pythonstart.BuildPlanMetadata{
+						Launch: true,
+					},
+				}))
+			})
+		})
+
 		context("When only a pixi.lock file is present", func() {
 			it.Before(func() {
 				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
@@ -604,6 +1235,114 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("When a Procfile and Python files in a package directory are present", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("web: python -m app\n"), os.ModePerm)).To(Succeed())
+				Expect(os.Mkdir(filepath.Join(workingDir, "app"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "app", "__main__.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("passes detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		context("When Python files are only present in a src layout package", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "src", "service"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "src", "service", "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("passes detection and reports the sources", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(ContainSubstring("Found 1 Python source file(s) within 3 directories of the application root"))
+				Expect(buffer.String()).To(ContainSubstring("src/service/app.py"))
+			})
+
+			context("when BP_PYTHON_SOURCE_DEPTH is lower than the package depth", func() {
+				it.Before(func() {
+					t.Setenv(pythonstart.SourceDepthEnv, "1")
+				})
+
+				it("fails detection", func() {
+					_, err := detect(packit.DetectContext{
+						WorkingDir: workingDir,
+					})
+					Expect(err).To(MatchError(ContainSubstring("No *.py")))
+				})
+			})
+		})
+
+		context("When Python files are only present in skipped directories", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, ".venv", "lib"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, ".venv", "lib", "site.py"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "build"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "build", "gen.py"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, ".gitignore"), []byte("build/\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("fails detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError(ContainSubstring("No *.py")))
+			})
+		})
+
+		context("When only a Procfile is present", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "Procfile"), []byte("web: python -m app\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("fails detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		context("when BP_PYTHON_APP_ROOT points to a subdirectory", func() {
+			it.Before(func() {
+				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "services", "api"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "services", "api", "requirements.txt"), []byte("flask\n"), os.ModePerm)).To(Succeed())
+				t.Setenv(pythonstart.AppRootEnv, "services/api")
+			})
+
+			it("passes detection", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			context("when the subdirectory has a uv.lock", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "services", "api", "uv.lock"), []byte{}, os.ModePerm)).To(Succeed())
+				})
+
+				it("makes every check in the subdirectory", func() {
+					result, err := detect(packit.DetectContext{
+						WorkingDir: workingDir,
+					})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Plan.Requires[0].Name).To(Equal("uv-environment"))
+				})
+			})
+		})
+
 		context("When no python related files are present", func() {
 			it.Before(func() {
 				Expect(os.RemoveAll(filepath.Join(workingDir, "x.py"))).To(Succeed())
@@ -613,7 +1352,7 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 				_, err := detect(packit.DetectContext{
 					WorkingDir: workingDir,
 				})
-				Expect(err).To(MatchError(ContainSubstring("No *.py, environment.yml, pixi.lock, requirements.txt, uv.lock, Pipfile.lock, pyproject.toml, or package-list.txt found")))
+				Expect(err).To(MatchError(ContainSubstring("No *.py, *.ipynb, environment.yml, pixi.lock, requirements.txt, uv.lock, Pipfile.lock, pdm.lock, setup.py, setup.cfg, pyproject.toml, or package-list.txt found")))
 			})
 		})
 	})
@@ -628,7 +1367,7 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 				_, err := detect(packit.DetectContext{
 					WorkingDir: workingDir,
 				})
-				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_LIVE_RELOAD_ENABLED value not-a-bool")))
+				Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_LIVE_RELOAD_ENABLED value "not-a-bool": expected true or false`)))
 			})
 		})
 
@@ -641,9 +1380,67 @@ func testDetect(t *testing.T, context spec.G, it spec.S) {
 				_, err := detect(packit.DetectContext{
 					WorkingDir: workingDir,
 				})
-				Expect(err).To(MatchError(ContainSubstring("failed to parse BP_ENABLE_PACKAGE_MANAGERS value not-a-bool")))
+				Expect(err).To(MatchError(ContainSubstring(`failed to parse BP_ENABLE_PACKAGE_MANAGERS value "not-a-bool": expected true or false`)))
 			})
 		})
 
+		context("when BP_PYTHON_APP_ROOT does not exist", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.AppRootEnv, "services/api")
+			})
+
+			it("returns an error", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError(ContainSubstring("failed to find BP_PYTHON_APP_ROOT directory")))
+			})
+		})
+
+		context("when several values are invalid", func() {
+			it.Before(func() {
+				t.Setenv(pythonstart.LiveReloadEnv, "not-a-bool")
+				t.Setenv(pythonstart.PackageManagersEnv, "not-a-bool")
+			})
+
+			it("reports every invalid value", func() {
+				_, err := detect(packit.DetectContext{
+					WorkingDir: workingDir,
+				})
+				Expect(err).To(MatchError(And(
+					ContainSubstring("failed to parse BP_LIVE_RELOAD_ENABLED value"),
+					ContainSubstring("failed to parse BP_ENABLE_PACKAGE_MANAGERS value"),
+				)))
+			})
+		})
 	})
 }
+
+// withoutDetectionReport returns the given plan without the detection report
+// entries, which are covered by their own tests.
+func withoutDetectionReport(plan packit.BuildPlan) packit.BuildPlan {
+	provides := []packit.BuildPlanProvision{}
+	for _, provision := range plan.Provides {
+		if provision.Name != pythonstart.DetectionReportPlanEntry {
+			provides = append(provides, provision)
+		}
+	}
+
+	var requires []packit.BuildPlanRequirement
+	for _, requirement := range plan.Requires {
+		if requirement.Name != pythonstart.DetectionReportPlanEntry {
+			requires = append(requires, requirement)
+		}
+	}
+
+	var or []packit.BuildPlan
+	for _, alternative := range plan.Or {
+		or = append(or, withoutDetectionReport(alternative))
+	}
+
+	return packit.BuildPlan{
+		Provides: provides,
+		Requires: requires,
+		Or:       or,
+	}
+}
@@ -0,0 +1,105 @@
+package pythonstart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/fs"
+)
+
+// DjangoProject describes a Django project managed through a manage.py file.
+type DjangoProject struct {
+	// SettingsModule is the DJANGO_SETTINGS_MODULE declared in manage.py.
+	SettingsModule string
+
+	// SettingsFile is the path of the settings module relative to the working
+	// directory. It is empty when the module cannot be found in the source.
+	SettingsFile string
+}
+
+var settingsModulePattern = regexp.MustCompile(`['"]DJANGO_SETTINGS_MODULE['"]\s*,\s*['"]([\w.]+)['"]`)
+
+// FindDjangoProject reads the DJANGO_SETTINGS_MODULE set by the manage.py
+// file in the given directory. The boolean result is false when there is no
+// manage.py or it does not set a settings module.
+func FindDjangoProject(workingDir string) (DjangoProject, bool, error) {
+	content, err := os.ReadFile(filepath.Join(workingDir, "manage.py"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DjangoProject{}, false, nil
+		}
+		return DjangoProject{}, false, fmt.Errorf("failed to read manage.py: %w", err)
+	}
+
+	matches := settingsModulePattern.FindSubmatch(content)
+	if matches == nil {
+		return DjangoProject{}, false, nil
+	}
+
+	project := DjangoProject{SettingsModule: string(matches[1])}
+
+	modulePath := filepath.Join(strings.Split(project.SettingsModule, ".")...)
+	for _, candidate := range []string{modulePath + ".py", filepath.Join(modulePath, "__init__.py")} {
+		exists, err := fs.Exists(filepath.Join(workingDir, candidate))
+		if err != nil {
+			return DjangoProject{}, false, fmt.Errorf("failed to stat %s: %w", candidate, err)
+		}
+
+		if exists {
+			project.SettingsFile = candidate
+			break
+		}
+	}
+
+	return project, true, nil
+}
+
+// ManagementProcesses returns the non-default process types that run common
+// manage.py commands. The runserver process serves the project with the
+// Django development server on $PORT, or on the given port when $PORT is not
+// set; it is not suitable for production and is never the web process.
+func (p DjangoProject) ManagementProcesses(defaultPort int) []packit.Process {
+	return []packit.Process{
+		{
+			Type:    "migrate",
+			Command: "python",
+			Args:    []string{"manage.py", "migrate"},
+			Direct:  true,
+		},
+		{
+			Type:    "shell",
+			Command: "python",
+			Args:    []string{"manage.py", "shell"},
+			Direct:  true,
+		},
+		{
+			Type:    "runserver",
+			Command: fmt.Sprintf("python manage.py runserver 0.0.0.0:${PORT:-%d}", defaultPort),
+		},
+	}
+}
+
+// djangoSettingsFiles returns the settings module of the Django project when
+// manage.py declares one that exists in the source, and any settings.py in a
+// package directory otherwise.
+func djangoSettingsFiles(workingDir string) ([]string, error) {
+	project, found, err := FindDjangoProject(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if found && project.SettingsFile != "" {
+		return []string{filepath.Join(workingDir, project.SettingsFile)}, nil
+	}
+
+	settingsFiles, err := filepath.Glob(filepath.Join(workingDir, "*", "settings.py"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find settings.py files: %w", err)
+	}
+
+	return settingsFiles, nil
+}
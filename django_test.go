@@ -0,0 +1,149 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+const managePy = `#!/usr/bin/env python
+import os
+import sys
+
+
+def main():
+    os.environ.setdefault("DJANGO_SETTINGS_MODULE", "config.settings.production")
+    from django.core.management import execute_from_command_line
+    execute_from_command_line(sys.argv)
+
+
+if __name__ == "__main__":
+    main()
+`
+
+func testDjango(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("FindDjangoProject", func() {
+		context("when manage.py sets the settings module", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "manage.py"), []byte(managePy), os.ModePerm)).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(workingDir, "config", "settings"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "config", "settings", "production.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the project and its settings file", func() {
+				project, found, err := pythonstart.FindDjangoProject(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(project).To(Equal(pythonstart.DjangoProject{
+					SettingsModule: "config.settings.production",
+					SettingsFile:   filepath.Join("config", "settings", "production.py"),
+				}))
+			})
+
+			context("when the settings module is a package", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "manage.py"), []byte(`os.environ.setdefault('DJANGO_SETTINGS_MODULE', 'config.settings')`), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(workingDir, "config", "settings", "__init__.py"), []byte{}, os.ModePerm)).To(Succeed())
+				})
+
+				it("finds the package __init__.py", func() {
+					project, found, err := pythonstart.FindDjangoProject(workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(project.SettingsFile).To(Equal(filepath.Join("config", "settings", "__init__.py")))
+				})
+			})
+
+			context("when the settings module is not in the source", func() {
+				it.Before(func() {
+					Expect(os.RemoveAll(filepath.Join(workingDir, "config"))).To(Succeed())
+				})
+
+				it("finds the project without a settings file", func() {
+					project, found, err := pythonstart.FindDjangoProject(workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(found).To(BeTrue())
+					Expect(project.SettingsFile).To(BeEmpty())
+				})
+			})
+		})
+
+		context("when there is no manage.py", func() {
+			it("does not find a project", func() {
+				_, found, err := pythonstart.FindDjangoProject(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("when manage.py does not set a settings module", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "manage.py"), []byte("print('hello')\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not find a project", func() {
+				_, found, err := pythonstart.FindDjangoProject(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when manage.py cannot be read", func() {
+				it.Before(func() {
+					Expect(os.Mkdir(filepath.Join(workingDir, "manage.py"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, _, err := pythonstart.FindDjangoProject(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to read manage.py")))
+				})
+			})
+		})
+	})
+
+	context("ManagementProcesses", func() {
+		it("returns the migrate, shell and runserver processes", func() {
+			Expect(pythonstart.DjangoProject{}.ManagementProcesses(8080)).To(Equal([]packit.Process{
+				{
+					Type:    "migrate",
+					Command: "python",
+					Args:    []string{"manage.py", "migrate"},
+					Direct:  true,
+				},
+				{
+					Type:    "shell",
+					Command: "python",
+					Args:    []string{"manage.py", "shell"},
+					Direct:  true,
+				},
+				{
+					Type:    "runserver",
+					Command: "python manage.py runserver 0.0.0.0:${PORT:-8080}",
+				},
+			}))
+		})
+	})
+}
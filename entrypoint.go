@@ -0,0 +1,107 @@
+package pythonstart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/paketo-buildpacks/packit/v2/fs"
+)
+
+// Entrypoint is the command inferred to start an application along with a
+// human-readable description of the rule that selected it.
+type Entrypoint struct {
+	Command string
+	Args    []string
+	Rule    string
+}
+
+var (
+	// conventionalEntrypoints are checked in order when no __main__.py or
+	// single main-guarded file is found.
+	conventionalEntrypoints = []string{"app.py", "main.py", "server.py"}
+
+	// nonEntrypoints are files that commonly carry a main guard but are tools
+	// rather than the application itself.
+	nonEntrypoints = map[string]bool{
+		"setup.py":    true,
+		"manage.py":   true,
+		"conftest.py": true,
+	}
+
+	mainGuardPattern = regexp.MustCompile(`(?m)^if\s+__name__\s*==\s*['"]__main__['"]\s*:`)
+)
+
+// InferEntrypoint inspects the top level of the given directory for the
+// Python file that starts the application. The rules are applied in order:
+//
+//  1. a __main__.py file
+//  2. a single *.py file containing an `if __name__ == "__main__":` guard
+//  3. an app.py, main.py or server.py file
+//
+// When no rule matches, the Python REPL is returned.
+func InferEntrypoint(workingDir string) (Entrypoint, error) {
+	mainFile, err := fs.Exists(filepath.Join(workingDir, "__main__.py"))
+	if err != nil {
+		return Entrypoint{}, fmt.Errorf("failed to stat __main__.py: %w", err)
+	}
+
+	if mainFile {
+		return Entrypoint{
+			Command: "python",
+			Args:    []string{"__main__.py"},
+			Rule:    "Found __main__.py",
+		}, nil
+	}
+
+	entries, err := os.ReadDir(workingDir)
+	if err != nil {
+		return Entrypoint{}, fmt.Errorf("failed to read working directory: %w", err)
+	}
+
+	var guarded []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".py" || nonEntrypoints[name] {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(workingDir, name))
+		if err != nil {
+			return Entrypoint{}, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		if mainGuardPattern.Match(content) {
+			guarded = append(guarded, name)
+		}
+	}
+
+	if len(guarded) == 1 {
+		return Entrypoint{
+			Command: "python",
+			Args:    []string{guarded[0]},
+			Rule:    fmt.Sprintf("Found __main__ guard in %s", guarded[0]),
+		}, nil
+	}
+
+	for _, name := range conventionalEntrypoints {
+		exists, err := fs.Exists(filepath.Join(workingDir, name))
+		if err != nil {
+			return Entrypoint{}, fmt.Errorf("failed to stat %s: %w", name, err)
+		}
+
+		if exists {
+			return Entrypoint{
+				Command: "python",
+				Args:    []string{name},
+				Rule:    fmt.Sprintf("Found conventional entrypoint %s", name),
+			}, nil
+		}
+	}
+
+	return Entrypoint{
+		Command: "python",
+		Rule:    "No entrypoint found, falling back to the Python REPL",
+	}, nil
+}
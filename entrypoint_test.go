@@ -0,0 +1,121 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testEntrypoint(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("InferEntrypoint", func() {
+		context("when there is a __main__.py", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "__main__.py"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("runs __main__.py", func() {
+				entrypoint, err := pythonstart.InferEntrypoint(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entrypoint).To(Equal(pythonstart.Entrypoint{
+					Command: "python",
+					Args:    []string{"__main__.py"},
+					Rule:    "Found __main__.py",
+				}))
+			})
+		})
+
+		context("when a single file has a __main__ guard", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte("import os\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "run.py"), []byte("if __name__ == '__main__':\n    main()\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "setup.py"), []byte("if __name__ == \"__main__\":\n    setup()\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("runs the guarded file", func() {
+				entrypoint, err := pythonstart.InferEntrypoint(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entrypoint).To(Equal(pythonstart.Entrypoint{
+					Command: "python",
+					Args:    []string{"run.py"},
+					Rule:    "Found __main__ guard in run.py",
+				}))
+			})
+		})
+
+		context("when several files have a __main__ guard", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "main.py"), []byte("if __name__ == '__main__':\n    main()\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "run.py"), []byte("if __name__ == '__main__':\n    main()\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("falls back to the conventional names", func() {
+				entrypoint, err := pythonstart.InferEntrypoint(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entrypoint).To(Equal(pythonstart.Entrypoint{
+					Command: "python",
+					Args:    []string{"main.py"},
+					Rule:    "Found conventional entrypoint main.py",
+				}))
+			})
+		})
+
+		context("when there are several conventional files", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "server.py"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("prefers app.py", func() {
+				entrypoint, err := pythonstart.InferEntrypoint(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entrypoint.Args).To(Equal([]string{"app.py"}))
+			})
+		})
+
+		context("when no entrypoint can be found", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "helpers.py"), []byte{}, os.ModePerm)).To(Succeed())
+			})
+
+			it("falls back to the REPL", func() {
+				entrypoint, err := pythonstart.InferEntrypoint(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entrypoint).To(Equal(pythonstart.Entrypoint{
+					Command: "python",
+					Rule:    "No entrypoint found, falling back to the Python REPL",
+				}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the working directory cannot be read", func() {
+				it("returns an error", func() {
+					_, err := pythonstart.InferEntrypoint(filepath.Join(workingDir, "missing"))
+					Expect(err).To(MatchError(ContainSubstring("failed to read working directory")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package pythonstart
+
+import (
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+const (
+	// PDMPlan is the name of the build plan alternative for PDM projects.
+	PDMPlan = "pdm"
+
+	// HatchPlan is the name of the build plan alternative for Hatch projects.
+	HatchPlan = "hatch"
+)
+
+// environmentRunners are the tools that start processes inside the project
+// environment of the build plan alternative they are keyed by.
+var environmentRunners = map[string]string{
+	PDMPlan:   "pdm",
+	HatchPlan: "hatch",
+}
+
+// runInEnvironment returns the given process wrapped with `<runner> run`, so
+// that it starts inside the environment of the project. Processes that are
+// run through a shell keep their shell semantics by running it inside the
+// environment.
+func runInEnvironment(process packit.Process, runner string) packit.Process {
+	if process.Direct {
+		process.Args = append([]string{"run", process.Command}, process.Args...)
+	} else {
+		process.Args = append([]string{"run", "bash", "-c", process.Command}, process.Args...)
+		process.Direct = true
+	}
+	process.Command = runner
+
+	return process
+}
@@ -0,0 +1,33 @@
+package pythonstart
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// MalformedFileError reports an application file that was read but could not
+// be parsed. Detect fails with it, so that a broken file does not select the
+// buildpack, while Build returns it as any other error.
+type MalformedFileError struct {
+	File string
+	Err  error
+}
+
+func (e MalformedFileError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %s", e.File, e.Err)
+}
+
+func (e MalformedFileError) Unwrap() error {
+	return e.Err
+}
+
+// malformedFileError wraps an error from parsing the named file in a
+// MalformedFileError, leaving errors from reading the file as they are.
+func malformedFileError(file string, err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return err
+	}
+	return MalformedFileError{File: file, Err: err}
+}
@@ -7,6 +7,7 @@ go 1.26.5
 
 require (
 	github.com/BurntSushi/toml v1.6.0
+	github.com/mattn/go-shellwords v1.0.16
 	github.com/onsi/gomega v1.42.1
 	github.com/paketo-buildpacks/occam v0.31.3
 	github.com/paketo-buildpacks/packit/v2 v2.25.6
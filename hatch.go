@@ -0,0 +1,55 @@
+package pythonstart
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// HatchScript is a script declared in the [tool.hatch.envs.default.scripts]
+// table of pyproject.toml.
+type HatchScript struct {
+	Name string
+}
+
+// Process returns a non-default process type that runs the script in the
+// default Hatch environment.
+func (s HatchScript) Process() packit.Process {
+	return packit.Process{
+		Type:    s.Name,
+		Command: "hatch",
+		Args:    []string{"run", s.Name},
+		Direct:  true,
+	}
+}
+
+// LoadHatchScripts returns the scripts of the default Hatch environment
+// declared in the pyproject.toml in the given directory, in the order they
+// are declared. Scripts whose names are not valid process types are ignored.
+func LoadHatchScripts(workingDir string) ([]HatchScript, error) {
+	var pyproject map[string]interface{}
+	metadata, err := toml.DecodeFile(filepath.Join(workingDir, "pyproject.toml"), &pyproject)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, malformedFileError("pyproject.toml", err)
+	}
+
+	var scripts []HatchScript
+	for _, key := range metadata.Keys() {
+		if len(key) != 6 || key[0] != "tool" || key[1] != "hatch" || key[2] != "envs" || key[3] != "default" || key[4] != "scripts" {
+			continue
+		}
+
+		if !processTypePattern.MatchString(key[5]) {
+			continue
+		}
+
+		scripts = append(scripts, HatchScript{Name: key[5]})
+	}
+
+	return scripts, nil
+}
@@ -0,0 +1,88 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testHatch(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("LoadHatchScripts", func() {
+		context("when the default environment declares scripts", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[tool.hatch.envs.default.scripts]
+serve = "uvicorn app:app --port 8000"
+migrate = ["alembic upgrade head", "python seed.py"]
+"not valid" = "echo"
+
+[tool.hatch.envs.test.scripts]
+cov = "pytest --cov"
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the scripts of the default environment in order", func() {
+				scripts, err := pythonstart.LoadHatchScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(Equal([]pythonstart.HatchScript{
+					{Name: "serve"},
+					{Name: "migrate"},
+				}))
+			})
+		})
+
+		context("when there is no pyproject.toml", func() {
+			it("returns no scripts", func() {
+				scripts, err := pythonstart.LoadHatchScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when pyproject.toml is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("%%%"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.LoadHatchScripts(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse pyproject.toml")))
+				})
+			})
+		})
+	})
+
+	context("HatchScript.Process", func() {
+		it("runs the script in the default environment", func() {
+			Expect(pythonstart.HatchScript{Name: "serve"}.Process()).To(Equal(packit.Process{
+				Type:    "serve",
+				Command: "hatch",
+				Args:    []string{"run", "serve"},
+				Direct:  true,
+			}))
+		})
+	})
+}
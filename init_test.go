@@ -9,7 +9,25 @@ import (
 
 func TestUnitPythonStart(t *testing.T) {
 	suite := spec.New("python-start", spec.Report(report.Terminal{}), spec.Sequential())
+	suite("ASGI", testASGI)
 	suite("Build", testBuild)
+	suite("Configuration", testConfiguration)
+	suite("Dashboard", testDashboard)
+	suite("Debug", testDebug)
+	suite("Dependencies", testDependencies)
 	suite("Detect", testDetect)
+	suite("Django", testDjango)
+	suite("Entrypoint", testEntrypoint)
+	suite("Hatch", testHatch)
+	suite("Notebook", testNotebook)
+	suite("Procfile", testProcfile)
+	suite("Pyproject", testPyproject)
+	suite("Reload", testReload)
+	suite("Report", testReport)
+	suite("Scripts", testScripts)
+	suite("Setuptools", testSetuptools)
+	suite("Sources", testSources)
+	suite("WSGI", testWSGI)
+	suite("Worker", testWorker)
 	suite.Run(t)
 }
@@ -92,25 +92,24 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+			))
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "8080"`,
+				`    PYTHONDONTWRITEBYTECODE -> "1"`,
+				`    PYTHONFAULTHANDLER      -> "1"`,
+				`    PYTHONUNBUFFERED        -> "1"`,
+				"",
 				"  Assigning launch processes:",
-				"    web (default): python",
+				"    web (default): python server.py",
 			))
 
 			container, err = docker.Container.Run.
-				WithTTY().
+				WithPublish("8080").
 				Execute(image.ID)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() string {
-				cLogs, err := docker.Container.Logs.Execute(container.ID)
-				Expect(err).NotTo(HaveOccurred())
-				return cLogs.String()
-			}).Should(
-				And(
-					MatchRegexp(`Python 3\.\d+\.\d+`),
-					ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-				),
-			)
+			Eventually(container).Should(Serve(ContainSubstring("hello world")).OnPort(8080))
 		})
 
 		it("builds an oci image with site-packages", func() {
@@ -132,25 +131,24 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+			))
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "8080"`,
+				`    PYTHONDONTWRITEBYTECODE -> "1"`,
+				`    PYTHONFAULTHANDLER      -> "1"`,
+				`    PYTHONUNBUFFERED        -> "1"`,
+				"",
 				"  Assigning launch processes:",
-				"    web (default): python",
+				"    web (default): python server.py",
 			))
 
 			container, err = docker.Container.Run.
-				WithTTY().
+				WithPublish("8080").
 				Execute(image.ID)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() string {
-				cLogs, err := docker.Container.Logs.Execute(container.ID)
-				Expect(err).NotTo(HaveOccurred())
-				return cLogs.String()
-			}).Should(
-				And(
-					MatchRegexp(`Python 3\.\d+\.\d+`),
-					ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-				),
-			)
+			Eventually(container).Should(Serve(ContainSubstring("Hello, world! Using Python")).OnPort(8080))
 		})
 
 		it("builds an oci image with site-packages and module", func() {
@@ -173,19 +171,82 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+			))
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "8080"`,
+				`    PYTHONDONTWRITEBYTECODE -> "1"`,
+				`    PYTHONFAULTHANDLER      -> "1"`,
+				`    PYTHONUNBUFFERED        -> "1"`,
+				"",
 				"  Assigning launch processes:",
-				"    web (default): python",
+				"    web (default): gunicorn module.wsgi:app --bind 0.0.0.0:${PORT:-8080}",
 			))
 
 			container, err = docker.Container.Run.
-				WithPublish("8000").
-				WithEntrypoint("launcher").
-				WithCommand("gunicorn -b 0.0.0.0 'module.wsgi:app'").
+				WithPublish("8080").
 				Execute(image.ID)
 			Expect(err).NotTo(HaveOccurred())
 
 			Eventually(container).
-				Should(Serve(MatchRegexp(`Hello, world! Using Python: 3\.\d+\.\d+ .*`)).OnPort(8000))
+				Should(Serve(MatchRegexp(`Hello, world! Using Python: 3\.\d+\.\d+ .*`)).OnPort(8080))
+		})
+
+		it("serves an inferred server on the port set by the platform", func() {
+			var err error
+			targetPath := "module"
+			source, err = sourceWithTarget(filepath.Join("testdata", "module_app"), &targetPath)
+			Expect(err).NotTo(HaveOccurred())
+
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithPullPolicy("never").
+				WithBuildpacks(
+					cpythonBuildpack,
+					pythonPackageManagersInstallBuildpack,
+					pythonPackageManagersRunBuildpack,
+					buildpack,
+				).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			container, err = docker.Container.Run.
+				WithEnv(map[string]string{"PORT": "9000"}).
+				WithPublish("9000").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).
+				Should(Serve(MatchRegexp(`Hello, world! Using Python: 3\.\d+\.\d+ .*`)).OnPort(9000))
+		})
+
+		it("exports the configured default port to the python launch command", func() {
+			var err error
+			source, err = occam.Source(filepath.Join("testdata", "default_app"))
+			Expect(err).NotTo(HaveOccurred())
+
+			var logs fmt.Stringer
+			image, logs, err = pack.WithNoColor().Build.
+				WithPullPolicy("never").
+				WithBuildpacks(
+					cpythonBuildpack,
+					buildpack,
+				).
+				WithEnv(map[string]string{"BP_PYTHON_DEFAULT_PORT": "5000"}).
+				Execute(name, source)
+			Expect(err).NotTo(HaveOccurred(), logs.String())
+
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "5000"`,
+			))
+
+			container, err = docker.Container.Run.
+				WithPublish("5000").
+				Execute(image.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(container).Should(Serve(ContainSubstring("hello world")).OnPort(5000))
 		})
 
 		it("builds an oci image with pipenv", func() {
@@ -207,25 +268,24 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+			))
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "8080"`,
+				`    PYTHONDONTWRITEBYTECODE -> "1"`,
+				`    PYTHONFAULTHANDLER      -> "1"`,
+				`    PYTHONUNBUFFERED        -> "1"`,
+				"",
 				"  Assigning launch processes:",
-				"    web (default): python",
+				"    web (default): python server.py",
 			))
 
 			container, err = docker.Container.Run.
-				WithTTY().
+				WithPublish("8080").
 				Execute(image.ID)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() string {
-				cLogs, err := docker.Container.Logs.Execute(container.ID)
-				Expect(err).NotTo(HaveOccurred())
-				return cLogs.String()
-			}).Should(
-				And(
-					MatchRegexp(`Python 3\.\d+\.\d+`),
-					ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-				),
-			)
+			Eventually(container).Should(Serve(ContainSubstring("Hello, world! Using Python")).OnPort(8080))
 		})
 
 		it("builds an oci image with conda-environment", func() {
@@ -246,25 +306,24 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+			))
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "8080"`,
+				`    PYTHONDONTWRITEBYTECODE -> "1"`,
+				`    PYTHONFAULTHANDLER      -> "1"`,
+				`    PYTHONUNBUFFERED        -> "1"`,
+				"",
 				"  Assigning launch processes:",
-				"    web (default): python",
+				"    web (default): python server.py",
 			))
 
 			container, err = docker.Container.Run.
-				WithTTY().
+				WithPublish("8080").
 				Execute(image.ID)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() string {
-				cLogs, err := docker.Container.Logs.Execute(container.ID)
-				Expect(err).NotTo(HaveOccurred())
-				return cLogs.String()
-			}).Should(
-				And(
-					MatchRegexp(`Python 3\.\d+\.\d+`),
-					ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-				),
-			)
+			Eventually(container).Should(Serve(ContainSubstring("Hello, world! Using Python")).OnPort(8080))
 		})
 
 		context("when building an app with poetry (dependency management only)", func() {
@@ -293,25 +352,24 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 				Expect(logs).To(ContainLines(
 					MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+				))
+				Expect(logs).To(ContainLines(
+					"  Configuring launch environment",
+					`    PORT                    -> "8080"`,
+					`    PYTHONDONTWRITEBYTECODE -> "1"`,
+					`    PYTHONFAULTHANDLER      -> "1"`,
+					`    PYTHONUNBUFFERED        -> "1"`,
+					"",
 					"  Assigning launch processes:",
-					"    web (default): python",
+					"    web (default): python server.py",
 				))
 
 				container, err = docker.Container.Run.
-					WithTTY().
+					WithPublish("8080").
 					Execute(image.ID)
 				Expect(err).NotTo(HaveOccurred())
 
-				Eventually(func() string {
-					cLogs, err := docker.Container.Logs.Execute(container.ID)
-					Expect(err).NotTo(HaveOccurred())
-					return cLogs.String()
-				}).Should(
-					And(
-						MatchRegexp(`Python 3\.\d+\.\d+`),
-						ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-					),
-				)
+				Eventually(container).Should(Serve(ContainSubstring("Hello, world! Using Python")).OnPort(8080))
 
 				container2, err = docker.Container.Run.
 					WithTTY().
@@ -346,25 +404,24 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+			))
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "8080"`,
+				`    PYTHONDONTWRITEBYTECODE -> "1"`,
+				`    PYTHONFAULTHANDLER      -> "1"`,
+				`    PYTHONUNBUFFERED        -> "1"`,
+				"",
 				"  Assigning launch processes:",
-				"    web (default): python",
+				"    web (default): python server.py",
 			))
 
 			container, err = docker.Container.Run.
-				WithTTY().
+				WithPublish("8080").
 				Execute(image.ID)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() string {
-				cLogs, err := docker.Container.Logs.Execute(container.ID)
-				Expect(err).NotTo(HaveOccurred())
-				return cLogs.String()
-			}).Should(
-				And(
-					MatchRegexp(`Python 3\.\d+\.\d+`),
-					ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-				),
-			)
+			Eventually(container).Should(Serve(ContainSubstring("Hello, world! Using Python")).OnPort(8080))
 		})
 
 		it("builds an oci image with uv-environment", func() {
@@ -385,25 +442,24 @@ func testDefault(t *testing.T, context spec.G, it spec.S) {
 
 			Expect(logs).To(ContainLines(
 				MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+			))
+			Expect(logs).To(ContainLines(
+				"  Configuring launch environment",
+				`    PORT                    -> "8080"`,
+				`    PYTHONDONTWRITEBYTECODE -> "1"`,
+				`    PYTHONFAULTHANDLER      -> "1"`,
+				`    PYTHONUNBUFFERED        -> "1"`,
+				"",
 				"  Assigning launch processes:",
-				"    web (default): python",
+				"    web (default): python server.py",
 			))
 
 			container, err = docker.Container.Run.
-				WithTTY().
+				WithPublish("8080").
 				Execute(image.ID)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(func() string {
-				cLogs, err := docker.Container.Logs.Execute(container.ID)
-				Expect(err).NotTo(HaveOccurred())
-				return cLogs.String()
-			}).Should(
-				And(
-					MatchRegexp(`Python 3\.\d+\.\d+`),
-					ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-				),
-			)
+			Eventually(container).Should(Serve(ContainSubstring("Hello, world! Using Python")).OnPort(8080))
 		})
 	})
 }
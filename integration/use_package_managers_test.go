@@ -72,25 +72,24 @@ func testUsePythonPackageManager(t *testing.T, context spec.G, it spec.S) {
 
 				Expect(logs).To(ContainLines(
 					MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+				))
+				Expect(logs).To(ContainLines(
+					"  Configuring launch environment",
+					`    PORT                    -> "8080"`,
+					`    PYTHONDONTWRITEBYTECODE -> "1"`,
+					`    PYTHONFAULTHANDLER      -> "1"`,
+					`    PYTHONUNBUFFERED        -> "1"`,
+					"",
 					"  Assigning launch processes:",
-					"    web (default): python",
+					"    web (default): python server.py",
 				))
 
 				container, err = docker.Container.Run.
-					WithTTY().
+					WithPublish("8080").
 					Execute(image.ID)
 				Expect(err).NotTo(HaveOccurred())
 
-				Eventually(func() string {
-					cLogs, err := docker.Container.Logs.Execute(container.ID)
-					Expect(err).NotTo(HaveOccurred())
-					return cLogs.String()
-				}).Should(
-					And(
-						MatchRegexp(`Python 3\.\d+\.\d+`),
-						ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-					),
-				)
+				Eventually(container).Should(Serve(ContainSubstring("Hello, world! Using Python")).OnPort(8080))
 			})
 
 			it("builds an oci image with python launch command", func() {
@@ -110,25 +109,24 @@ func testUsePythonPackageManager(t *testing.T, context spec.G, it spec.S) {
 
 				Expect(logs).To(ContainLines(
 					MatchRegexp(fmt.Sprintf(`%s \d+\.\d+\.\d+`, buildpackInfo.Buildpack.Name)),
+				))
+				Expect(logs).To(ContainLines(
+					"  Configuring launch environment",
+					`    PORT                    -> "8080"`,
+					`    PYTHONDONTWRITEBYTECODE -> "1"`,
+					`    PYTHONFAULTHANDLER      -> "1"`,
+					`    PYTHONUNBUFFERED        -> "1"`,
+					"",
 					"  Assigning launch processes:",
-					"    web (default): python",
+					"    web (default): python server.py",
 				))
 
 				container, err = docker.Container.Run.
-					WithTTY().
+					WithPublish("8080").
 					Execute(image.ID)
 				Expect(err).NotTo(HaveOccurred())
 
-				Eventually(func() string {
-					cLogs, err := docker.Container.Logs.Execute(container.ID)
-					Expect(err).NotTo(HaveOccurred())
-					return cLogs.String()
-				}).Should(
-					And(
-						MatchRegexp(`Python 3\.\d+\.\d+`),
-						ContainSubstring(`Type "help", "copyright", "credits" or "license" for more information.`),
-					),
-				)
+				Eventually(container).Should(Serve(ContainSubstring("hello world")).OnPort(8080))
 			})
 		})
 
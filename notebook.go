@@ -0,0 +1,93 @@
+package pythonstart
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// NotebookServers are the servers the web process of a notebook project can
+// run, in order of preference.
+var NotebookServers = []string{"jupyterlab", "voila"}
+
+// NotebookTokenPolicies are the token policies of the notebook servers. With
+// generate, the server requires a token, which is $JUPYTER_TOKEN when it is
+// set and is generated and printed at startup otherwise. With none, the
+// server can be used without authentication.
+var NotebookTokenPolicies = []string{"generate", "none"}
+
+// NotebookExecutors are the tools the notebook process can execute a notebook
+// with, in order of preference.
+var NotebookExecutors = []string{"papermill", "nbconvert"}
+
+// SelectNotebookServer returns the configured notebook server, or the first
+// one declared as a dependency, falling back to JupyterLab.
+func SelectNotebookServer(config Configuration, dependencies Dependencies) string {
+	return selectTool(config.NotebookServer, NotebookServers, dependencies, "jupyterlab")
+}
+
+// SelectNotebookExecutor returns the configured notebook executor, or the
+// first one declared as a dependency, falling back to nbconvert, which ships
+// with Jupyter.
+func SelectNotebookExecutor(config Configuration, dependencies Dependencies) string {
+	return selectTool(config.NotebookExecutor, NotebookExecutors, dependencies, "nbconvert")
+}
+
+func selectTool(configured string, tools []string, dependencies Dependencies, fallback string) string {
+	if configured != "" {
+		return configured
+	}
+	for _, tool := range tools {
+		if dependencies.Has(tool) {
+			return tool
+		}
+	}
+	return fallback
+}
+
+// NotebookServerProcess returns the web process that serves the notebooks of
+// the application with the given server on $PORT, or on the configured
+// default port when $PORT is not set.
+func NotebookServerProcess(server string, config Configuration) packit.Process {
+	var command string
+	switch server {
+	case "voila":
+		command = fmt.Sprintf("voila --no-browser --Voila.ip=0.0.0.0 --port=${PORT:-%d}", config.DefaultPort)
+		if config.NotebookToken == "generate" {
+			command += " --token"
+		}
+	default:
+		command = fmt.Sprintf("jupyter lab --no-browser --ip=0.0.0.0 --port=${PORT:-%d}", config.DefaultPort)
+		if config.NotebookToken == "none" {
+			command += " --IdentityProvider.token=''"
+		}
+	}
+
+	return packit.Process{
+		Type:    "web",
+		Command: command,
+		Default: true,
+	}
+}
+
+// NotebookProcess returns a process of type notebook that executes the
+// configured notebook headlessly with the given executor. The executed copy of
+// the notebook, with its outputs, is written to $TMPDIR.
+func NotebookProcess(executor string, config Configuration) packit.Process {
+	notebook := shellQuote(config.Notebook)
+
+	var command string
+	switch executor {
+	case "papermill":
+		output := shellQuote(filepath.Base(config.Notebook))
+		command = fmt.Sprintf("papermill --log-output %s ${TMPDIR:-/tmp}/%s", notebook, output)
+	default:
+		command = fmt.Sprintf("jupyter nbconvert --to notebook --execute %s --output-dir ${TMPDIR:-/tmp}", notebook)
+	}
+
+	return packit.Process{
+		Type:    "notebook",
+		Command: command,
+	}
+}
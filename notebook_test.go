@@ -0,0 +1,94 @@
+package pythonstart_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testNotebook(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		config pythonstart.Configuration
+	)
+
+	it.Before(func() {
+		config = pythonstart.Configuration{
+			DefaultPort:   8080,
+			NotebookToken: "generate",
+		}
+	})
+
+	context("SelectNotebookServer", func() {
+		it("prefers the configured server", func() {
+			config.NotebookServer = "voila"
+			Expect(pythonstart.SelectNotebookServer(config, pythonstart.Dependencies{"jupyterlab": true})).To(Equal("voila"))
+		})
+
+		it("selects the first declared server", func() {
+			Expect(pythonstart.SelectNotebookServer(config, pythonstart.Dependencies{"voila": true})).To(Equal("voila"))
+			Expect(pythonstart.SelectNotebookServer(config, pythonstart.Dependencies{"voila": true, "jupyterlab": true})).To(Equal("jupyterlab"))
+		})
+
+		it("falls back to JupyterLab", func() {
+			Expect(pythonstart.SelectNotebookServer(config, pythonstart.Dependencies{})).To(Equal("jupyterlab"))
+		})
+	})
+
+	context("SelectNotebookExecutor", func() {
+		it("selects papermill when it is declared", func() {
+			Expect(pythonstart.SelectNotebookExecutor(config, pythonstart.Dependencies{"papermill": true})).To(Equal("papermill"))
+		})
+
+		it("falls back to nbconvert", func() {
+			Expect(pythonstart.SelectNotebookExecutor(config, pythonstart.Dependencies{})).To(Equal("nbconvert"))
+		})
+	})
+
+	context("NotebookServerProcess", func() {
+		it("serves JupyterLab on $PORT", func() {
+			Expect(pythonstart.NotebookServerProcess("jupyterlab", config)).To(Equal(packit.Process{
+				Type:    "web",
+				Command: "jupyter lab --no-browser --ip=0.0.0.0 --port=${PORT:-8080}",
+				Default: true,
+			}))
+		})
+
+		it("serves Voila with a token", func() {
+			Expect(pythonstart.NotebookServerProcess("voila", config).Command).To(Equal("voila --no-browser --Voila.ip=0.0.0.0 --port=${PORT:-8080} --token"))
+		})
+
+		context("when the token policy is none", func() {
+			it.Before(func() {
+				config.NotebookToken = "none"
+			})
+
+			it("serves without authentication", func() {
+				Expect(pythonstart.NotebookServerProcess("jupyterlab", config).Command).To(Equal("jupyter lab --no-browser --ip=0.0.0.0 --port=${PORT:-8080} --IdentityProvider.token=''"))
+				Expect(pythonstart.NotebookServerProcess("voila", config).Command).To(Equal("voila --no-browser --Voila.ip=0.0.0.0 --port=${PORT:-8080}"))
+			})
+		})
+	})
+
+	context("NotebookProcess", func() {
+		it.Before(func() {
+			config.Notebook = "notebooks/daily report.ipynb"
+		})
+
+		it("executes the notebook with papermill", func() {
+			Expect(pythonstart.NotebookProcess("papermill", config)).To(Equal(packit.Process{
+				Type:    "notebook",
+				Command: "papermill --log-output 'notebooks/daily report.ipynb' ${TMPDIR:-/tmp}/'daily report.ipynb'",
+			}))
+		})
+
+		it("executes the notebook with nbconvert", func() {
+			Expect(pythonstart.NotebookProcess("nbconvert", config).Command).To(Equal("jupyter nbconvert --to notebook --execute 'notebooks/daily report.ipynb' --output-dir ${TMPDIR:-/tmp}"))
+		})
+	})
+}
@@ -0,0 +1,105 @@
+package pythonstart
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+var (
+	procfileEntryPattern = regexp.MustCompile(`^([A-Za-z0-9_-]+)\s*:\s*(.+)$`)
+
+	// shellPattern matches commands that rely on the shell for pipes,
+	// redirection, command chaining, substitution, globbing or environment
+	// variable expansion and so cannot be run directly.
+	shellPattern = regexp.MustCompile("[|&;<>()$`*?~]|^[A-Za-z_][A-Za-z0-9_]*=")
+)
+
+// ParseProcfile reads the Procfile at the given path and returns a launch
+// process for each of its entries. The "web" entry is marked as the default
+// process. Commands that require a shell are run through one; all other
+// commands are split into a command and its arguments and run directly.
+func ParseProcfile(path string) ([]packit.Process, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Procfile: %w", err)
+	}
+	defer file.Close()
+
+	var processes []packit.Process
+	seen := map[string]bool{}
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		matches := procfileEntryPattern.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, fmt.Errorf("failed to parse Procfile line %d: expected '<type>: <command>', got %q", lineNumber, line)
+		}
+
+		processType, command := matches[1], strings.TrimSpace(matches[2])
+		if seen[processType] {
+			return nil, fmt.Errorf("failed to parse Procfile line %d: duplicate process type %q", lineNumber, processType)
+		}
+		seen[processType] = true
+
+		process, err := newProcess(processType, command)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Procfile line %d: %w", lineNumber, err)
+		}
+		process.Default = processType == "web"
+
+		processes = append(processes, process)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Procfile: %w", err)
+	}
+
+	return processes, nil
+}
+
+// newProcess builds a launch process from a command line, running it through
+// a shell only when the command needs one.
+func newProcess(processType, command string) (packit.Process, error) {
+	if strings.TrimSpace(command) == "" {
+		return packit.Process{}, fmt.Errorf("empty command for process type %q", processType)
+	}
+
+	if shellPattern.MatchString(command) {
+		return packit.Process{
+			Type:    processType,
+			Command: command,
+		}, nil
+	}
+
+	words, err := shellwords.Parse(command)
+	if err != nil {
+		return packit.Process{}, fmt.Errorf("invalid command %q: %w", command, err)
+	}
+
+	if len(words) == 0 {
+		return packit.Process{}, fmt.Errorf("empty command for process type %q", processType)
+	}
+
+	process := packit.Process{
+		Type:    processType,
+		Command: words[0],
+		Direct:  true,
+	}
+
+	if len(words) > 1 {
+		process.Args = words[1:]
+	}
+
+	return process, nil
+}
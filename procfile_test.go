@@ -0,0 +1,118 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testProcfile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+		path       string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(workingDir, "Procfile")
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("ParseProcfile", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(path, []byte(`# process types
+web: gunicorn app:app --workers "2"
+worker: python worker.py | tee worker.log
+
+release: FLASK_APP=app flask db upgrade
+clock: python clock.py --interval $INTERVAL
+`), os.ModePerm)).To(Succeed())
+		})
+
+		it("returns a process for every entry", func() {
+			processes, err := pythonstart.ParseProcfile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(processes).To(Equal([]packit.Process{
+				{
+					Type:    "web",
+					Command: "gunicorn",
+					Args:    []string{"app:app", "--workers", "2"},
+					Default: true,
+					Direct:  true,
+				},
+				{
+					Type:    "worker",
+					Command: "python worker.py | tee worker.log",
+				},
+				{
+					Type:    "release",
+					Command: "FLASK_APP=app flask db upgrade",
+				},
+				{
+					Type:    "clock",
+					Command: "python clock.py --interval $INTERVAL",
+				},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when the Procfile does not exist", func() {
+				it.Before(func() {
+					Expect(os.Remove(path)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.ParseProcfile(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to open Procfile")))
+				})
+			})
+
+			context("when a line is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(path, []byte("web: python app.py\nnot a process\n"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.ParseProcfile(path)
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse Procfile line 2: expected '<type>: <command>', got "not a process"`)))
+				})
+			})
+
+			context("when a process type is declared twice", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(path, []byte("web: python app.py\nweb: python other.py\n"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.ParseProcfile(path)
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse Procfile line 2: duplicate process type "web"`)))
+				})
+			})
+
+			context("when a command has unbalanced quotes", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(path, []byte("web: python 'app.py\n"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.ParseProcfile(path)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse Procfile line 1: invalid command")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package pythonstart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PackageManager names the tool that installs a Python project.
+type PackageManager string
+
+const (
+	PoetryManager PackageManager = "poetry"
+	UVManager     PackageManager = "uv"
+	PDMManager    PackageManager = "pdm"
+	HatchManager  PackageManager = "hatch"
+	PipManager    PackageManager = "pip"
+)
+
+// Pyproject describes the tables of a pyproject.toml that tell which package
+// manager installs the project.
+type Pyproject struct {
+	// BuildBackend is the [build-system].build-backend of the project.
+	BuildBackend string
+
+	// HasProject reports whether the file declares a PEP 621 [project] table.
+	HasProject bool
+
+	// HasPoetry, HasUV, HasPDM and HasHatch report whether the file declares
+	// the [tool.poetry], [tool.uv], [tool.pdm] and [tool.hatch] tables.
+	HasPoetry bool
+	HasUV     bool
+	HasPDM    bool
+	HasHatch  bool
+}
+
+// backendManagers maps the module prefix of a build backend to the package
+// manager of the projects that use it.
+var backendManagers = []struct {
+	prefix  string
+	manager PackageManager
+}{
+	{"poetry.core", PoetryManager},
+	{"poetry.masonry", PoetryManager},
+	{"uv_build", UVManager},
+	{"pdm.backend", PDMManager},
+	{"pdm.pep517", PDMManager},
+	{"hatchling", HatchManager},
+	{"setuptools", PipManager},
+	{"flit_core", PipManager},
+	{"maturin", PipManager},
+	{"scikit_build_core", PipManager},
+	{"mesonpy", PipManager},
+}
+
+// LoadPyproject reads the pyproject.toml in the given directory. It returns
+// false when there is no such file.
+func LoadPyproject(workingDir string) (Pyproject, bool, error) {
+	var content struct {
+		BuildSystem struct {
+			BuildBackend string `toml:"build-backend"`
+		} `toml:"build-system"`
+		Project map[string]interface{} `toml:"project"`
+		Tool    struct {
+			Poetry map[string]interface{} `toml:"poetry"`
+			UV     map[string]interface{} `toml:"uv"`
+			PDM    map[string]interface{} `toml:"pdm"`
+			Hatch  map[string]interface{} `toml:"hatch"`
+		} `toml:"tool"`
+	}
+	_, err := toml.DecodeFile(filepath.Join(workingDir, "pyproject.toml"), &content)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Pyproject{}, false, nil
+		}
+		return Pyproject{}, false, malformedFileError("pyproject.toml", err)
+	}
+
+	return Pyproject{
+		BuildBackend: content.BuildSystem.BuildBackend,
+		HasProject:   content.Project != nil,
+		HasPoetry:    content.Tool.Poetry != nil,
+		HasUV:        content.Tool.UV != nil,
+		HasPDM:       content.Tool.PDM != nil,
+		HasHatch:     content.Tool.Hatch != nil,
+	}, true, nil
+}
+
+// Classify returns the package manager that installs the project along with
+// the evidence for it. Tool tables take precedence over the build backend,
+// since a project commonly builds with a backend that is not its package
+// manager. It returns an empty PackageManager when the file does not describe
+// an installable project.
+func (p Pyproject) Classify() (PackageManager, string) {
+	switch {
+	case p.HasPoetry:
+		return PoetryManager, "[tool.poetry] table"
+	case p.HasUV:
+		return UVManager, "[tool.uv] table"
+	case p.HasPDM:
+		return PDMManager, "[tool.pdm] table"
+	case p.HasHatch:
+		return HatchManager, "[tool.hatch] table"
+	}
+
+	for _, backend := range backendManagers {
+		if p.BuildBackend == backend.prefix || strings.HasPrefix(p.BuildBackend, backend.prefix+".") || strings.HasPrefix(p.BuildBackend, backend.prefix+":") {
+			return backend.manager, fmt.Sprintf("build-backend %s", p.BuildBackend)
+		}
+	}
+
+	if p.HasProject {
+		return PipManager, "[project] table"
+	}
+
+	return "", ""
+}
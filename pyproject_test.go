@@ -0,0 +1,116 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testPyproject(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("LoadPyproject", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[build-system]
+requires = ["hatchling"]
+build-backend = "hatchling.build"
+
+[project]
+name = "app"
+
+[tool.hatch.envs.default]
+dependencies = ["pytest"]
+`), os.ModePerm)).To(Succeed())
+		})
+
+		it("reads the tables that identify the package manager", func() {
+			pyproject, found, err := pythonstart.LoadPyproject(workingDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(pyproject).To(Equal(pythonstart.Pyproject{
+				BuildBackend: "hatchling.build",
+				HasProject:   true,
+				HasHatch:     true,
+			}))
+		})
+
+		context("when there is no pyproject.toml", func() {
+			it.Before(func() {
+				Expect(os.Remove(filepath.Join(workingDir, "pyproject.toml"))).To(Succeed())
+			})
+
+			it("does not find a project", func() {
+				_, found, err := pythonstart.LoadPyproject(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when pyproject.toml is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("%%%"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, _, err := pythonstart.LoadPyproject(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse pyproject.toml")))
+				})
+			})
+		})
+	})
+
+	context("Classify", func() {
+		it("prefers tool tables over the build backend", func() {
+			manager, evidence := pythonstart.Pyproject{BuildBackend: "hatchling.build", HasUV: true}.Classify()
+			Expect(manager).To(Equal(pythonstart.UVManager))
+			Expect(evidence).To(Equal("[tool.uv] table"))
+		})
+
+		it("classifies projects by their build backend", func() {
+			for backend, expected := range map[string]pythonstart.PackageManager{
+				"poetry.core.masonry.api":          pythonstart.PoetryManager,
+				"uv_build":                         pythonstart.UVManager,
+				"pdm.backend":                      pythonstart.PDMManager,
+				"hatchling.build":                  pythonstart.HatchManager,
+				"setuptools.build_meta":            pythonstart.PipManager,
+				"setuptools.build_meta:__legacy__": pythonstart.PipManager,
+				"flit_core.buildapi":               pythonstart.PipManager,
+			} {
+				manager, evidence := pythonstart.Pyproject{BuildBackend: backend}.Classify()
+				Expect(manager).To(Equal(expected), backend)
+				Expect(evidence).To(Equal("build-backend " + backend))
+			}
+		})
+
+		it("classifies a PEP 621 project without a known backend as pip", func() {
+			manager, _ := pythonstart.Pyproject{BuildBackend: "custom_backend", HasProject: true}.Classify()
+			Expect(manager).To(Equal(pythonstart.PipManager))
+		})
+
+		it("does not classify a file that only configures tools", func() {
+			manager, _ := pythonstart.Pyproject{}.Classify()
+			Expect(manager).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package pythonstart
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// ReloadProcess returns a process of type reload that runs the given process
+// under watchexec, restarting it whenever a file changes in the configured
+// watch paths of the given application directory. Processes that need a
+// shell are run through bash. The reload process is not a default process.
+func ReloadProcess(process packit.Process, appDir string, config Configuration) packit.Process {
+	args := []string{
+		"--restart",
+		"--debounce", fmt.Sprintf("%dms", config.ReloadDebounce.Milliseconds()),
+		"--stop-signal", config.ReloadSignal,
+		"--shell", "none",
+	}
+
+	for _, path := range config.ReloadWatchPaths {
+		args = append(args, "--watch", filepath.Join(appDir, path))
+	}
+
+	for _, pattern := range config.ReloadIgnore {
+		args = append(args, "--ignore", pattern)
+	}
+
+	args = append(args, "--")
+	if process.Direct {
+		args = append(args, process.Command)
+	} else {
+		args = append(args, "bash", "-c", process.Command)
+	}
+	args = append(args, process.Args...)
+
+	return packit.Process{
+		Type:             "reload",
+		Command:          "watchexec",
+		Args:             args,
+		Direct:           true,
+		WorkingDirectory: process.WorkingDirectory,
+	}
+}
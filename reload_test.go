@@ -0,0 +1,76 @@
+package pythonstart_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testReload(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		config pythonstart.Configuration
+	)
+
+	it.Before(func() {
+		config = pythonstart.Configuration{
+			ReloadWatchPaths: []string{".", "templates"},
+			ReloadIgnore:     []string{"__pycache__", "*.pyc"},
+			ReloadDebounce:   2 * time.Second,
+			ReloadSignal:     "SIGINT",
+		}
+	})
+
+	context("ReloadProcess", func() {
+		it("runs a direct process under watchexec", func() {
+			process := pythonstart.ReloadProcess(packit.Process{
+				Type:             "web",
+				Command:          "python",
+				Args:             []string{"app.py"},
+				Direct:           true,
+				Default:          true,
+				WorkingDirectory: "/workspace/api",
+			}, "/workspace/api", config)
+
+			Expect(process).To(Equal(packit.Process{
+				Type:    "reload",
+				Command: "watchexec",
+				Args: []string{
+					"--restart",
+					"--debounce", "2000ms",
+					"--stop-signal", "SIGINT",
+					"--shell", "none",
+					"--watch", "/workspace/api",
+					"--watch", "/workspace/api/templates",
+					"--ignore", "__pycache__",
+					"--ignore", "*.pyc",
+					"--",
+					"python", "app.py",
+				},
+				Direct:           true,
+				WorkingDirectory: "/workspace/api",
+			}))
+		})
+
+		context("when the process runs through a shell", func() {
+			it("runs the shell under watchexec", func() {
+				process := pythonstart.ReloadProcess(packit.Process{
+					Type:    "web",
+					Command: "gunicorn app:app --bind 0.0.0.0:${PORT:-8080}",
+				}, "/workspace", config)
+
+				Expect(process.Direct).To(BeTrue())
+				Expect(process.Args[len(process.Args)-4:]).To(Equal([]string{
+					"--",
+					"bash", "-c", "gunicorn app:app --bind 0.0.0.0:${PORT:-8080}",
+				}))
+			})
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package pythonstart
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+)
+
+// DetectionReportPlanEntry is the build plan entry this buildpack provides and
+// requires itself in order to hand its DetectionReport from detect to build.
+const DetectionReportPlanEntry = "python-start"
+
+// DetectionReportFile is the name of the JSON copy of the DetectionReport
+// written to the layers directory when BP_PYTHON_DETECTION_REPORT is enabled.
+const DetectionReportFile = "python-start-detection.json"
+
+// DetectionReport explains which build plan alternatives were offered during
+// detection and why. Every alternative carries its own copy of the report
+// with Plan set to its name, so the copy read during build names the
+// alternative the lifecycle resolved.
+type DetectionReport struct {
+	Plan          string           `toml:"plan" json:"plan"`
+	Files         []string         `toml:"files,omitempty" json:"files"`
+	Pyproject     string           `toml:"pyproject,omitempty" json:"pyproject,omitempty"`
+	PythonSources int              `toml:"python-sources" json:"python_sources"`
+	Notebooks     int              `toml:"notebooks,omitempty" json:"notebooks,omitempty"`
+	Settings      []Setting        `toml:"settings,omitempty" json:"settings"`
+	Offered       []string         `toml:"offered,omitempty" json:"offered"`
+	Suppressed    []SuppressedPlan `toml:"suppressed,omitempty" json:"suppressed"`
+}
+
+// SuppressedPlan is a build plan alternative that was not offered.
+type SuppressedPlan struct {
+	Plan   string `toml:"plan" json:"plan"`
+	Reason string `toml:"reason" json:"reason"`
+}
+
+// ReadDetectionReport returns the report carried by the DetectionReportPlanEntry
+// of the given build plan entries. It returns false when there is no such
+// entry.
+func ReadDetectionReport(entries []packit.BuildpackPlanEntry) (DetectionReport, bool, error) {
+	for _, entry := range entries {
+		if entry.Name != DetectionReportPlanEntry {
+			continue
+		}
+
+		buffer := bytes.NewBuffer(nil)
+		err := toml.NewEncoder(buffer).Encode(entry.Metadata)
+		if err != nil {
+			return DetectionReport{}, false, fmt.Errorf("failed to read detection report: %w", err)
+		}
+
+		var report DetectionReport
+		_, err = toml.NewDecoder(buffer).Decode(&report)
+		if err != nil {
+			return DetectionReport{}, false, fmt.Errorf("failed to read detection report: %w", err)
+		}
+
+		return report, true, nil
+	}
+
+	return DetectionReport{}, false, nil
+}
+
+// Log prints the report. The settings read during detection are only printed
+// at the debug level since the build prints its own.
+func (r DetectionReport) Log(logger scribe.Emitter) {
+	logger.Process("Detection report:")
+	logger.Subprocess("Resolved plan:  %s", r.Plan)
+	if len(r.Files) > 0 {
+		logger.Subprocess("Files found:    %s", strings.Join(r.Files, ", "))
+	}
+	if r.Pyproject != "" {
+		logger.Subprocess("pyproject.toml: %s", r.Pyproject)
+	}
+	logger.Subprocess("Python sources: %d", r.PythonSources)
+	if r.Notebooks > 0 {
+		logger.Subprocess("Notebooks:      %d", r.Notebooks)
+	}
+	logger.Subprocess("Plans offered:  %s", strings.Join(r.Offered, ", "))
+	for _, plan := range r.Suppressed {
+		logger.Subprocess("Suppressed %s: %s", plan.Plan, plan.Reason)
+	}
+
+	if len(r.Settings) > 0 {
+		logger.Debug.Subprocess("Settings read during detection:")
+		for _, setting := range r.Settings {
+			logger.Debug.Action("%s -> %q (%s)", setting.Name, setting.Value, setting.Source)
+		}
+	}
+	logger.Break()
+}
+
+// WriteJSON writes the report as indented JSON to the given path.
+func (r DetectionReport) WriteJSON(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
@@ -0,0 +1,127 @@
+package pythonstart_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/packit/v2"
+	"github.com/paketo-buildpacks/packit/v2/scribe"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testReport(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("ReadDetectionReport", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte("flask\n"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`
+[project]
+name = "app"
+dependencies = ["flask"]
+`), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte("from flask import Flask\napp = Flask(__name__)\n"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, "analysis.ipynb"), []byte("{}"), os.ModePerm)).To(Succeed())
+		})
+
+		it("reads the report of the detection phase back from the build plan", func() {
+			result, err := pythonstart.Detect(scribe.NewEmitter(bytes.NewBuffer(nil)))(packit.DetectContext{
+				WorkingDir: workingDir,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			requirement := result.Plan.Requires[len(result.Plan.Requires)-1]
+			Expect(requirement.Name).To(Equal(pythonstart.DetectionReportPlanEntry))
+			detected := requirement.Metadata.(pythonstart.DetectionReport)
+			Expect(detected.Files).NotTo(BeEmpty())
+			Expect(detected.Pyproject).NotTo(BeEmpty())
+			Expect(detected.Settings).NotTo(BeEmpty())
+			Expect(detected.Suppressed).NotTo(BeEmpty())
+
+			// The lifecycle hands the build plan to the build as TOML.
+			buffer := bytes.NewBuffer(nil)
+			Expect(toml.NewEncoder(buffer).Encode(result.Plan)).To(Succeed())
+
+			var plan packit.BuildPlan
+			_, err = toml.NewDecoder(buffer).Decode(&plan)
+			Expect(err).NotTo(HaveOccurred())
+
+			var entries []packit.BuildpackPlanEntry
+			for _, requirement := range plan.Requires {
+				metadata, ok := requirement.Metadata.(map[string]interface{})
+				Expect(ok).To(BeTrue())
+				entries = append(entries, packit.BuildpackPlanEntry{Name: requirement.Name, Metadata: metadata})
+			}
+
+			report, ok, err := pythonstart.ReadDetectionReport(entries)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(report).To(Equal(detected))
+		})
+
+		it("returns false when there is no report", func() {
+			_, ok, err := pythonstart.ReadDetectionReport([]packit.BuildpackPlanEntry{{Name: "cpython"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	context("WriteJSON", func() {
+		it("writes the report as indented JSON", func() {
+			path := filepath.Join(workingDir, pythonstart.DetectionReportFile)
+			Expect(pythonstart.DetectionReport{
+				Plan:          "pip",
+				Files:         []string{"requirements.txt"},
+				PythonSources: 2,
+				Settings: []pythonstart.Setting{
+					{Name: pythonstart.StartCommandEnv, Source: "default"},
+				},
+				Offered: []string{"pip"},
+				Suppressed: []pythonstart.SuppressedPlan{
+					{Plan: "simple", Reason: "WSGI application app:app will be served by gunicorn"},
+				},
+			}.WriteJSON(path)).To(Succeed())
+
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(HaveSuffix("}\n"))
+			Expect(string(content)).To(ContainSubstring("\n  \"plan\": \"pip\",\n"))
+
+			var report map[string]interface{}
+			Expect(json.Unmarshal(content, &report)).To(Succeed())
+			Expect(report).To(Equal(map[string]interface{}{
+				"plan":           "pip",
+				"files":          []interface{}{"requirements.txt"},
+				"python_sources": float64(2),
+				"settings": []interface{}{
+					map[string]interface{}{"name": "BP_PYTHON_START_COMMAND", "value": "", "source": "default"},
+				},
+				"offered": []interface{}{"pip"},
+				"suppressed": []interface{}{
+					map[string]interface{}{"plan": "simple", "reason": "WSGI application app:app will be served by gunicorn"},
+				},
+			}))
+		})
+	})
+}
@@ -9,8 +9,10 @@ import (
 )
 
 func main() {
+	logger := scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))
+
 	packit.Run(
-		pythonstart.Detect(),
-		pythonstart.Build(scribe.NewEmitter(os.Stdout).WithLevel(os.Getenv("BP_LOG_LEVEL"))),
+		pythonstart.Detect(logger),
+		pythonstart.Build(logger),
 	)
 }
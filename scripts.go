@@ -0,0 +1,65 @@
+package pythonstart
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// ConsoleScript is an executable that is installed along with the project
+// and declared in a scripts table of pyproject.toml.
+type ConsoleScript struct {
+	Name string
+}
+
+// Process returns a non-default process type that runs the script.
+func (s ConsoleScript) Process() packit.Process {
+	return packit.Process{
+		Type:    s.Name,
+		Command: s.Name,
+		Direct:  true,
+	}
+}
+
+var processTypePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// LoadConsoleScripts returns the scripts declared in the [project.scripts]
+// (PEP 621) and [tool.poetry.scripts] tables of the pyproject.toml in the
+// given directory, in the order they are declared. Scripts whose names are
+// not valid process types are ignored.
+func LoadConsoleScripts(workingDir string) ([]ConsoleScript, error) {
+	var pyproject map[string]interface{}
+	metadata, err := toml.DecodeFile(filepath.Join(workingDir, "pyproject.toml"), &pyproject)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, malformedFileError("pyproject.toml", err)
+	}
+
+	var scripts []ConsoleScript
+	seen := map[string]bool{}
+	for _, key := range metadata.Keys() {
+		var name string
+		switch {
+		case len(key) == 3 && key[0] == "project" && key[1] == "scripts":
+			name = key[2]
+		case len(key) == 4 && key[0] == "tool" && key[1] == "poetry" && key[2] == "scripts":
+			name = key[3]
+		default:
+			continue
+		}
+
+		if seen[name] || !processTypePattern.MatchString(name) {
+			continue
+		}
+		seen[name] = true
+
+		scripts = append(scripts, ConsoleScript{Name: name})
+	}
+
+	return scripts, nil
+}
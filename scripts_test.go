@@ -0,0 +1,103 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testScripts(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("LoadConsoleScripts", func() {
+		context("when pyproject.toml declares scripts", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte(`[project]
+name = "service"
+
+[project.scripts]
+serve = "service.cli:serve"
+import-data = "service.cli:import_data"
+"bad name" = "service.cli:bad"
+
+[tool.poetry.scripts]
+serve = "service.cli:serve"
+report = { reference = "service.cli:report", type = "console" }
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the scripts in declaration order", func() {
+				scripts, err := pythonstart.LoadConsoleScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(Equal([]pythonstart.ConsoleScript{
+					{Name: "serve"},
+					{Name: "import-data"},
+					{Name: "report"},
+				}))
+			})
+		})
+
+		context("when pyproject.toml declares no scripts", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("[project]\nname = \"service\"\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns no scripts", func() {
+				scripts, err := pythonstart.LoadConsoleScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(BeEmpty())
+			})
+		})
+
+		context("when there is no pyproject.toml", func() {
+			it("returns no scripts", func() {
+				scripts, err := pythonstart.LoadConsoleScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when pyproject.toml is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "pyproject.toml"), []byte("%%%"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.LoadConsoleScripts(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to parse pyproject.toml")))
+				})
+			})
+		})
+	})
+
+	context("ConsoleScript.Process", func() {
+		it("runs the script directly", func() {
+			Expect(pythonstart.ConsoleScript{Name: "serve"}.Process()).To(Equal(packit.Process{
+				Type:    "serve",
+				Command: "serve",
+				Direct:  true,
+			}))
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package pythonstart
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	setupPyConsoleScriptsPattern = regexp.MustCompile(`['"]console_scripts['"]\s*:\s*[\[(]([^\])]*)[\])]`)
+	quotedStringPattern          = regexp.MustCompile(`['"]([^'"]*)['"]`)
+)
+
+// LoadSetuptoolsScripts returns the console_scripts entry points declared in
+// the [options.entry_points] section of the setup.cfg and in the setup()
+// call of the setup.py in the given directory, in the order they are
+// declared. Scripts whose names are not valid process types are ignored.
+func LoadSetuptoolsScripts(workingDir string) ([]ConsoleScript, error) {
+	var entries []string
+
+	cfgEntries, err := readSetupCfgConsoleScripts(filepath.Join(workingDir, "setup.cfg"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read setup.cfg: %w", err)
+	}
+	entries = append(entries, cfgEntries...)
+
+	content, err := os.ReadFile(filepath.Join(workingDir, "setup.py"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read setup.py: %w", err)
+	}
+
+	for _, match := range setupPyConsoleScriptsPattern.FindAllStringSubmatch(string(content), -1) {
+		for _, entry := range quotedStringPattern.FindAllStringSubmatch(match[1], -1) {
+			entries = append(entries, entry[1])
+		}
+	}
+
+	var scripts []ConsoleScript
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		name, _, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || seen[name] || !processTypePattern.MatchString(name) {
+			continue
+		}
+		seen[name] = true
+
+		scripts = append(scripts, ConsoleScript{Name: name})
+	}
+
+	return scripts, nil
+}
+
+// readSetupCfgConsoleScripts returns the entries of the console_scripts
+// option of the [options.entry_points] section of the given setup.cfg.
+func readSetupCfgConsoleScripts(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var (
+		entries   []string
+		section   string
+		inScripts bool
+	)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			inScripts = false
+			continue
+		}
+
+		if section != "options.entry_points" {
+			continue
+		}
+
+		// Options start at the beginning of the line, while the values that
+		// continue them are indented.
+		if line[0] != ' ' && line[0] != '\t' {
+			key, value, _ := strings.Cut(line, "=")
+			inScripts = strings.TrimSpace(key) == "console_scripts"
+			if inScripts && strings.TrimSpace(value) != "" {
+				entries = append(entries, strings.TrimSpace(value))
+			}
+			continue
+		}
+
+		if inScripts {
+			entries = append(entries, trimmed)
+		}
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,123 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSetuptools(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("LoadSetuptoolsScripts", func() {
+		context("when setup.cfg declares console scripts", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "setup.cfg"), []byte(`[metadata]
+name = service
+
+[options.entry_points]
+console_scripts = serve = service.cli:serve
+    import-data = service.cli:import_data
+    # comments are ignored
+    not valid = service.cli:broken
+gui_scripts =
+    viewer = service.gui:main
+
+[options]
+packages = find:
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the scripts in order", func() {
+				scripts, err := pythonstart.LoadSetuptoolsScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(Equal([]pythonstart.ConsoleScript{
+					{Name: "serve"},
+					{Name: "import-data"},
+				}))
+			})
+		})
+
+		context("when setup.py declares console scripts", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "setup.py"), []byte(`from setuptools import setup
+
+setup(
+    name="service",
+    entry_points={
+        "console_scripts": [
+            "serve=service.cli:serve",
+            'worker = service.worker:main',
+        ],
+        "gui_scripts": ["viewer = service.gui:main"],
+    },
+)
+`), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns the scripts in order", func() {
+				scripts, err := pythonstart.LoadSetuptoolsScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(Equal([]pythonstart.ConsoleScript{
+					{Name: "serve"},
+					{Name: "worker"},
+				}))
+			})
+
+			context("when setup.cfg declares the same script", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(workingDir, "setup.cfg"), []byte("[options.entry_points]\nconsole_scripts =\n    worker = service.worker:run\n"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns it once, in setup.cfg order", func() {
+					scripts, err := pythonstart.LoadSetuptoolsScripts(workingDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(scripts).To(Equal([]pythonstart.ConsoleScript{
+						{Name: "worker"},
+						{Name: "serve"},
+					}))
+				})
+			})
+		})
+
+		context("when there are no setuptools files", func() {
+			it("returns no scripts", func() {
+				scripts, err := pythonstart.LoadSetuptoolsScripts(workingDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(scripts).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when setup.cfg cannot be read", func() {
+				it.Before(func() {
+					Expect(os.Mkdir(filepath.Join(workingDir, "setup.cfg"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.LoadSetuptoolsScripts(workingDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to read setup.cfg")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package pythonstart
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// skippedDirectories never contain application sources.
+var skippedDirectories = map[string]bool{
+	".venv":        true,
+	"node_modules": true,
+	".git":         true,
+	"__pycache__":  true,
+
+	".ipynb_checkpoints": true,
+}
+
+// FindPythonSources walks the given directory and returns the paths, relative
+// to it, of the *.py files found at most maxDepth directories below it, in
+// lexical order. Virtual environments, node_modules, .git and __pycache__
+// directories are skipped, as are the paths ignored by the .gitignore and
+// .dockerignore files at the top of the directory.
+func FindPythonSources(workingDir string, maxDepth int) ([]string, error) {
+	sources, err := findFiles(workingDir, maxDepth, ".py")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find Python sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// FindNotebooks returns the paths, relative to the given directory, of the
+// Jupyter notebooks found at most maxDepth directories below it, in lexical
+// order. Directories are skipped as they are by FindPythonSources, along with
+// the checkpoints Jupyter keeps next to notebooks.
+func FindNotebooks(workingDir string, maxDepth int) ([]string, error) {
+	notebooks, err := findFiles(workingDir, maxDepth, ".ipynb")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notebooks: %w", err)
+	}
+
+	return notebooks, nil
+}
+
+func findFiles(workingDir string, maxDepth int, ext string) ([]string, error) {
+	patterns, err := loadIgnorePatterns(workingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	err = filepath.WalkDir(workingDir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(workingDir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			if skippedDirectories[entry.Name()] || strings.Count(rel, "/") >= maxDepth || ignored(patterns, rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(rel) == ext && !ignored(patterns, rel, false) {
+			files = append(files, rel)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// ignorePattern is a single pattern of an ignore file. Negated patterns are
+// not supported and are dropped when the file is read.
+type ignorePattern struct {
+	pattern  string
+	anchored bool
+	dirOnly  bool
+}
+
+func (p ignorePattern) matches(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	// Unanchored patterns match the same number of trailing path segments,
+	// which is the base name unless the pattern started with **/.
+	name := rel
+	if !p.anchored {
+		segments := strings.Split(rel, "/")
+		count := strings.Count(p.pattern, "/") + 1
+		if count > len(segments) {
+			return false
+		}
+		name = strings.Join(segments[len(segments)-count:], "/")
+	}
+
+	matched, _ := path.Match(p.pattern, name)
+	return matched
+}
+
+func ignored(patterns []ignorePattern, rel string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if pattern.matches(rel, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadIgnorePatterns reads the .gitignore and .dockerignore files in the given
+// directory. Patterns from .gitignore without a slash match at any depth,
+// while patterns from .dockerignore are relative to the directory. Patterns
+// from either file that start with **/ match at any depth.
+func loadIgnorePatterns(workingDir string) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	for _, file := range []string{".gitignore", ".dockerignore"} {
+		lines, err := readIgnoreFile(filepath.Join(workingDir, file))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		for _, line := range lines {
+			var pattern ignorePattern
+
+			anyDepth := strings.HasPrefix(line, "**/")
+			line = strings.TrimPrefix(line, "**/")
+			if strings.HasSuffix(line, "/") {
+				pattern.dirOnly = true
+				line = strings.TrimSuffix(line, "/")
+			}
+			pattern.anchored = !anyDepth && (file == ".dockerignore" || strings.Contains(line, "/"))
+			pattern.pattern = strings.TrimPrefix(line, "/")
+
+			if pattern.pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+
+	return patterns, nil
+}
+
+func readIgnoreFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
@@ -0,0 +1,142 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSources(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, file := range []string{
+			"manage.py",
+			"README.md",
+			"src/service/__init__.py",
+			"src/service/api/routes.py",
+			"src/service/api/v1/handlers.py",
+			".venv/lib/site.py",
+			"node_modules/gyp/gyp.py",
+			".git/hooks/hook.py",
+			"src/service/__pycache__/cached.py",
+			"docs/conf.py",
+			"scripts/generated_pb2.py",
+			"tests/test_app.py",
+		} {
+			Expect(os.MkdirAll(filepath.Join(workingDir, filepath.Dir(file)), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(workingDir, file), []byte{}, os.ModePerm)).To(Succeed())
+		}
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("FindPythonSources", func() {
+		it("finds the sources within the depth and outside of skipped directories", func() {
+			sources, err := pythonstart.FindPythonSources(workingDir, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sources).To(Equal([]string{
+				"docs/conf.py",
+				"manage.py",
+				"scripts/generated_pb2.py",
+				"src/service/__init__.py",
+				"src/service/api/routes.py",
+				"tests/test_app.py",
+			}))
+		})
+
+		it("only finds top-level sources at depth 0", func() {
+			sources, err := pythonstart.FindPythonSources(workingDir, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sources).To(Equal([]string{"manage.py"}))
+		})
+
+		context("when there are ignore files", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, ".gitignore"), []byte("# generated code\n*_pb2.py\n/docs/\n!keep.py\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, ".dockerignore"), []byte("tests\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("skips the ignored paths", func() {
+				sources, err := pythonstart.FindPythonSources(workingDir, 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sources).To(Equal([]string{
+					"manage.py",
+					"src/service/__init__.py",
+					"src/service/api/routes.py",
+				}))
+			})
+		})
+
+		context("when ignore patterns match at any depth", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(workingDir, "src", "service", "tests"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "src", "service", "tests", "test_api.py"), []byte{}, os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, ".gitignore"), []byte("**/service/api\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, ".dockerignore"), []byte("**/tests\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("skips the nested paths", func() {
+				sources, err := pythonstart.FindPythonSources(workingDir, 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sources).To(Equal([]string{
+					"docs/conf.py",
+					"manage.py",
+					"scripts/generated_pb2.py",
+					"src/service/__init__.py",
+				}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when .gitignore cannot be read", func() {
+				it.Before(func() {
+					Expect(os.Mkdir(filepath.Join(workingDir, ".gitignore"), os.ModePerm)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := pythonstart.FindPythonSources(workingDir, 3)
+					Expect(err).To(MatchError(ContainSubstring("failed to read .gitignore")))
+				})
+			})
+		})
+	})
+
+	context("FindNotebooks", func() {
+		it.Before(func() {
+			for _, file := range []string{
+				"analysis.ipynb",
+				"notebooks/report.ipynb",
+				"notebooks/.ipynb_checkpoints/report-checkpoint.ipynb",
+				".venv/share/jupyter/example.ipynb",
+			} {
+				Expect(os.MkdirAll(filepath.Join(workingDir, filepath.Dir(file)), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, file), []byte("{}"), os.ModePerm)).To(Succeed())
+			}
+		})
+
+		it("finds the notebooks outside of checkpoints and skipped directories", func() {
+			notebooks, err := pythonstart.FindNotebooks(workingDir, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(notebooks).To(Equal([]string{
+				"analysis.ipynb",
+				"notebooks/report.ipynb",
+			}))
+		})
+	})
+}
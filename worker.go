@@ -0,0 +1,166 @@
+package pythonstart
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/paketo-buildpacks/packit/v2"
+)
+
+// TaskQueue describes a background task queue framework whose workers can be
+// run as process types.
+type TaskQueue struct {
+	Name    string
+	Package string
+
+	// definition matches the source of an application module. When it has a
+	// submatch, that is the name of the application instance, which the
+	// command line references after the module and separator.
+	definition *regexp.Regexp
+	separator  string
+	processes  func(reference string, concurrency int) []packit.Process
+}
+
+// WorkerApp is an application module of a task queue.
+type WorkerApp struct {
+	Queue TaskQueue
+	App   AppObject
+}
+
+// Reference returns the application in the form understood by the command
+// line of the task queue: module:instance for Celery, module.instance for
+// Huey and the module alone for RQ and Dramatiq.
+func (w WorkerApp) Reference() string {
+	if w.App.Callable == "" {
+		return w.App.Module
+	}
+	return w.App.Module + w.Queue.separator + w.App.Callable
+}
+
+// Processes returns the non-default process types that run the workers of
+// the application, running the given number of tasks at once. A concurrency
+// of zero keeps the task queue default.
+func (w WorkerApp) Processes(concurrency int) []packit.Process {
+	return w.Queue.processes(w.Reference(), concurrency)
+}
+
+var (
+	// taskQueues are listed in order of preference.
+	taskQueues = []TaskQueue{
+		{
+			Name:       "Celery",
+			Package:    "celery",
+			definition: regexp.MustCompile(`(?m)^(\w+)\s*=\s*(?:celery\.)?Celery\(`),
+			separator:  ":",
+			processes: func(reference string, concurrency int) []packit.Process {
+				worker := []string{"--app", reference, "worker", "--loglevel=INFO"}
+				if concurrency > 0 {
+					worker = append(worker, "--concurrency", strconv.Itoa(concurrency))
+				}
+				return []packit.Process{
+					{Type: "worker", Command: "celery", Args: worker, Direct: true},
+					{Type: "beat", Command: "celery", Args: []string{"--app", reference, "beat", "--loglevel=INFO"}, Direct: true},
+				}
+			},
+		},
+		{
+			Name:       "RQ",
+			Package:    "rq",
+			definition: importPattern("rq"),
+			processes: func(_ string, concurrency int) []packit.Process {
+				args := []string{"worker"}
+				if concurrency > 0 {
+					args = []string{"worker-pool", "--num-workers", strconv.Itoa(concurrency)}
+				}
+				return []packit.Process{
+					{Type: "worker", Command: "rq", Args: args, Direct: true},
+				}
+			},
+		},
+		{
+			Name:       "Dramatiq",
+			Package:    "dramatiq",
+			definition: importPattern("dramatiq"),
+			processes: func(reference string, concurrency int) []packit.Process {
+				args := []string{reference}
+				if concurrency > 0 {
+					args = append(args, "--processes", strconv.Itoa(concurrency))
+				}
+				return []packit.Process{
+					{Type: "worker", Command: "dramatiq", Args: args, Direct: true},
+				}
+			},
+		},
+		{
+			Name:       "Huey",
+			Package:    "huey",
+			definition: regexp.MustCompile(`(?m)^(\w+)\s*=\s*(?:huey\.)?\w*Huey\(`),
+			separator:  ".",
+			processes: func(reference string, concurrency int) []packit.Process {
+				args := []string{reference}
+				if concurrency > 0 {
+					args = append(args, "--workers", strconv.Itoa(concurrency))
+				}
+				return []packit.Process{
+					{Type: "worker", Command: "huey_consumer", Args: args, Direct: true},
+				}
+			},
+		},
+	}
+
+	workerModules = []string{"celery.py", "tasks.py", "worker.py", "jobs.py", "app.py", "main.py"}
+)
+
+// FindWorkerApp looks for the application module of a task queue declared in
+// the given dependencies. The celery.py, tasks.py, worker.py, jobs.py, app.py
+// and main.py files are checked in order, first at the top level of the given
+// directory and then in its package directories. Celery and Huey modules must
+// create the application instance, while RQ and Dramatiq modules only need to
+// import the framework. The boolean result is false when no application
+// module can be found.
+func FindWorkerApp(workingDir string, dependencies Dependencies) (WorkerApp, bool, error) {
+	var queues []TaskQueue
+	for _, queue := range taskQueues {
+		if dependencies.Has(queue.Package) {
+			queues = append(queues, queue)
+		}
+	}
+
+	if len(queues) == 0 {
+		return WorkerApp{}, false, nil
+	}
+
+	candidates, err := candidateFiles(workingDir, workerModules)
+	if err != nil {
+		return WorkerApp{}, false, err
+	}
+
+	for _, queue := range queues {
+		for _, path := range candidates {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return WorkerApp{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+
+			matches := queue.definition.FindSubmatch(content)
+			if matches == nil {
+				continue
+			}
+
+			relPath := relativePath(workingDir, path)
+			app := AppObject{
+				Module: moduleName(relPath),
+				Source: relPath,
+			}
+			if len(matches) > 1 {
+				app.Callable = string(matches[1])
+			}
+
+			return WorkerApp{Queue: queue, App: app}, true, nil
+		}
+	}
+
+	return WorkerApp{}, false, nil
+}
@@ -0,0 +1,173 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/v2"
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testWorker(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	find := func(requirements string) (pythonstart.WorkerApp, bool) {
+		Expect(os.WriteFile(filepath.Join(workingDir, "requirements.txt"), []byte(requirements), os.ModePerm)).To(Succeed())
+
+		dependencies, err := pythonstart.LoadDependencies(workingDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		worker, found, err := pythonstart.FindWorkerApp(workingDir, dependencies)
+		Expect(err).NotTo(HaveOccurred())
+		return worker, found
+	}
+
+	context("FindWorkerApp", func() {
+		context("when a package creates a Celery application", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "proj"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "proj", "celery.py"), []byte("from celery import Celery\n\ncelery_app = Celery('proj')\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the application instance", func() {
+				worker, found := find("celery[redis]==5.4.0\n")
+				Expect(found).To(BeTrue())
+				Expect(worker.Queue.Name).To(Equal("Celery"))
+				Expect(worker.App).To(Equal(pythonstart.AppObject{
+					Module:   "proj.celery",
+					Callable: "celery_app",
+					Source:   "proj/celery.py",
+				}))
+				Expect(worker.Reference()).To(Equal("proj.celery:celery_app"))
+			})
+
+			it("does not find it when Celery is not declared", func() {
+				_, found := find("redis\n")
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("when a module creates a Huey instance", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("from huey import RedisHuey\n\nhuey = RedisHuey('app')\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the instance", func() {
+				worker, found := find("huey\n")
+				Expect(found).To(BeTrue())
+				Expect(worker.Queue.Name).To(Equal("Huey"))
+				Expect(worker.Reference()).To(Equal("tasks.huey"))
+			})
+		})
+
+		context("when a module imports RQ or Dramatiq", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "jobs.py"), []byte("import dramatiq\n\n@dramatiq.actor\ndef send(): pass\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the module", func() {
+				worker, found := find("dramatiq[redis]\n")
+				Expect(found).To(BeTrue())
+				Expect(worker.Queue.Name).To(Equal("Dramatiq"))
+				Expect(worker.Reference()).To(Equal("jobs"))
+			})
+		})
+
+		context("when several task queues are declared", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "worker.py"), []byte("from rq import Queue\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "app.py"), []byte("import celery\n\napp = celery.Celery()\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("prefers Celery", func() {
+				worker, found := find("rq\ncelery\n")
+				Expect(found).To(BeTrue())
+				Expect(worker.Queue.Name).To(Equal("Celery"))
+				Expect(worker.Reference()).To(Equal("app:app"))
+			})
+		})
+
+		context("when no module defines the application", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("import celery\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not find an application", func() {
+				_, found := find("celery\n")
+				Expect(found).To(BeFalse())
+			})
+		})
+	})
+
+	context("Processes", func() {
+		it("runs a Celery worker and beat", func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("app = Celery()\n"), os.ModePerm)).To(Succeed())
+			worker, found := find("celery\n")
+			Expect(found).To(BeTrue())
+
+			Expect(worker.Processes(0)).To(Equal([]packit.Process{
+				{
+					Type:    "worker",
+					Command: "celery",
+					Args:    []string{"--app", "tasks:app", "worker", "--loglevel=INFO"},
+					Direct:  true,
+				},
+				{
+					Type:    "beat",
+					Command: "celery",
+					Args:    []string{"--app", "tasks:app", "beat", "--loglevel=INFO"},
+					Direct:  true,
+				},
+			}))
+
+			Expect(worker.Processes(4)[0].Args).To(Equal([]string{"--app", "tasks:app", "worker", "--loglevel=INFO", "--concurrency", "4"}))
+		})
+
+		it("runs an RQ worker pool when concurrency is set", func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("from rq import Queue\n"), os.ModePerm)).To(Succeed())
+			worker, found := find("rq\n")
+			Expect(found).To(BeTrue())
+
+			Expect(worker.Processes(0)).To(Equal([]packit.Process{
+				{Type: "worker", Command: "rq", Args: []string{"worker"}, Direct: true},
+			}))
+			Expect(worker.Processes(3)).To(Equal([]packit.Process{
+				{Type: "worker", Command: "rq", Args: []string{"worker-pool", "--num-workers", "3"}, Direct: true},
+			}))
+		})
+
+		it("passes the concurrency to Dramatiq and Huey", func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("import dramatiq\n"), os.ModePerm)).To(Succeed())
+			worker, found := find("dramatiq\n")
+			Expect(found).To(BeTrue())
+			Expect(worker.Processes(2)).To(Equal([]packit.Process{
+				{Type: "worker", Command: "dramatiq", Args: []string{"tasks", "--processes", "2"}, Direct: true},
+			}))
+
+			Expect(os.WriteFile(filepath.Join(workingDir, "tasks.py"), []byte("huey = SqliteHuey()\n"), os.ModePerm)).To(Succeed())
+			worker, found = find("huey\n")
+			Expect(found).To(BeTrue())
+			Expect(worker.Processes(2)).To(Equal([]packit.Process{
+				{Type: "worker", Command: "huey_consumer", Args: []string{"tasks.huey", "--workers", "2"}, Direct: true},
+			}))
+		})
+	})
+}
@@ -0,0 +1,162 @@
+package pythonstart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AppObject references an application callable by the dotted path of its
+// module and its attribute name, along with a description of where the
+// reference was found.
+type AppObject struct {
+	Module   string
+	Callable string
+	Source   string
+}
+
+// String returns the reference in the module:callable form understood by
+// Python application servers.
+func (a AppObject) String() string {
+	return fmt.Sprintf("%s:%s", a.Module, a.Callable)
+}
+
+var (
+	appReferencePattern       = regexp.MustCompile(`^[A-Za-z_][\w.]*:[A-Za-z_]\w*$`)
+	wsgiApplicationPattern    = regexp.MustCompile(`(?m)^WSGI_APPLICATION\s*=\s*['"]([\w.]+)\.(\w+)['"]`)
+	wsgiCallableNames         = []string{"application", "app"}
+	callableDefinitionPattern = `(?m)^(?:%[1]s\s*[:=]|def\s+%[1]s\s*\(|from\s+\S+\s+import\s+.*\b%[1]s\b)`
+)
+
+// FindWSGIApp locates the WSGI callable of the application in the given
+// directory. The sources are checked in order:
+//
+//  1. a module:callable reference configured with BP_PYTHON_WSGI_APP
+//  2. the WSGI_APPLICATION setting of a Django project
+//  3. an application or app callable in a wsgi.py file at the top level or
+//     in a package directory
+//
+// The boolean result is false when no WSGI callable can be found.
+func FindWSGIApp(workingDir, reference string) (AppObject, bool, error) {
+	if reference != "" {
+		app, err := parseAppReference(reference)
+		if err != nil {
+			return AppObject{}, false, err
+		}
+		app.Source = WSGIAppEnv
+		return app, true, nil
+	}
+
+	app, found, err := findSettingReference(workingDir, wsgiApplicationPattern, "WSGI_APPLICATION")
+	if err != nil || found {
+		return app, found, err
+	}
+
+	candidates, err := candidateFiles(workingDir, []string{"wsgi.py"})
+	if err != nil {
+		return AppObject{}, false, err
+	}
+
+	for _, path := range candidates {
+		app, found, err := findCallable(workingDir, path, wsgiCallableNames)
+		if err != nil || found {
+			return app, found, err
+		}
+	}
+
+	return AppObject{}, false, nil
+}
+
+// findSettingReference looks for a dotted module.callable reference assigned
+// to the given setting in the settings module of a Django project.
+func findSettingReference(workingDir string, pattern *regexp.Regexp, setting string) (AppObject, bool, error) {
+	settingsFiles, err := djangoSettingsFiles(workingDir)
+	if err != nil {
+		return AppObject{}, false, err
+	}
+
+	for _, path := range settingsFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return AppObject{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		matches := pattern.FindSubmatch(content)
+		if matches != nil {
+			return AppObject{
+				Module:   string(matches[1]),
+				Callable: string(matches[2]),
+				Source:   fmt.Sprintf("%s in %s", setting, relativePath(workingDir, path)),
+			}, true, nil
+		}
+	}
+
+	return AppObject{}, false, nil
+}
+
+// findCallable looks for the first of the given names that is defined,
+// assigned or imported at the top level of the Python file at path.
+func findCallable(workingDir, path string, names []string) (AppObject, bool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return AppObject{}, false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	for _, name := range names {
+		pattern := regexp.MustCompile(fmt.Sprintf(callableDefinitionPattern, regexp.QuoteMeta(name)))
+		if pattern.Match(content) {
+			relPath := relativePath(workingDir, path)
+			return AppObject{
+				Module:   moduleName(relPath),
+				Callable: name,
+				Source:   relPath,
+			}, true, nil
+		}
+	}
+
+	return AppObject{}, false, nil
+}
+
+// candidateFiles returns the existing files with the given names at the top
+// level of the directory followed by those in its package directories.
+func candidateFiles(workingDir string, names []string) ([]string, error) {
+	var candidates []string
+	for _, pattern := range []string{"", "*"} {
+		for _, name := range names {
+			matches, err := filepath.Glob(filepath.Join(workingDir, pattern, name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to find %s files: %w", name, err)
+			}
+			candidates = append(candidates, matches...)
+		}
+	}
+	return candidates, nil
+}
+
+func parseAppReference(reference string) (AppObject, error) {
+	if !appReferencePattern.MatchString(reference) {
+		return AppObject{}, fmt.Errorf("expected <module>:<callable>")
+	}
+
+	module, callable, _ := strings.Cut(reference, ":")
+	return AppObject{
+		Module:   module,
+		Callable: callable,
+	}, nil
+}
+
+// moduleName converts a relative path to a Python file into a dotted module
+// name.
+func moduleName(relPath string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(filepath.ToSlash(relPath), ".py"), "/", ".")
+}
+
+func relativePath(workingDir, path string) string {
+	rel, err := filepath.Rel(workingDir, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
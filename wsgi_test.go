@@ -0,0 +1,138 @@
+package pythonstart_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pythonstart "github.com/paketo-buildpacks/python-start"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testWSGI(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	context("FindWSGIApp", func() {
+		context("when there is a top-level wsgi.py", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("from flask import Flask\n\napp = Flask(__name__)\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the callable", func() {
+				app, found, err := pythonstart.FindWSGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "wsgi",
+					Callable: "app",
+					Source:   "wsgi.py",
+				}))
+				Expect(app.String()).To(Equal("wsgi:app"))
+			})
+		})
+
+		context("when a package has a wsgi.py that imports the callable", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "module"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "module", "wsgi.py"), []byte("from .server import app\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("finds the callable", func() {
+				app, found, err := pythonstart.FindWSGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "module.wsgi",
+					Callable: "app",
+					Source:   "module/wsgi.py",
+				}))
+			})
+		})
+
+		context("when wsgi.py defines both application and app", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("app = make_app()\napplication = app\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("prefers application", func() {
+				app, found, err := pythonstart.FindWSGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app.Callable).To(Equal("application"))
+			})
+		})
+
+		context("when a Django project sets WSGI_APPLICATION", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, "mysite"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "mysite", "settings.py"), []byte("WSGI_APPLICATION = 'mysite.wsgi.application'\n"), os.ModePerm)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("app = None\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("uses the setting", func() {
+				app, found, err := pythonstart.FindWSGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "mysite.wsgi",
+					Callable: "application",
+					Source:   "WSGI_APPLICATION in mysite/settings.py",
+				}))
+			})
+		})
+
+		context("when a reference is configured", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("app = None\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("uses the reference", func() {
+				app, found, err := pythonstart.FindWSGIApp(workingDir, "service.entry:create")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(app).To(Equal(pythonstart.AppObject{
+					Module:   "service.entry",
+					Callable: "create",
+					Source:   "BP_PYTHON_WSGI_APP",
+				}))
+			})
+		})
+
+		context("when there is no WSGI callable", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(workingDir, "wsgi.py"), []byte("import os\n"), os.ModePerm)).To(Succeed())
+			})
+
+			it("does not find an app", func() {
+				_, found, err := pythonstart.FindWSGIApp(workingDir, "")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the configured reference is malformed", func() {
+				it("returns an error", func() {
+					_, _, err := pythonstart.FindWSGIApp(workingDir, "service.entry")
+					Expect(err).To(MatchError("expected <module>:<callable>"))
+				})
+			})
+		})
+	})
+}